@@ -0,0 +1,243 @@
+package game
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/logger"
+)
+
+type TokenPurpose string
+
+const (
+	TokenVerify        TokenPurpose = "verify"
+	TokenResetPassword TokenPurpose = "reset"
+
+	accountKeyName = "account_token_key" // row in server_keys
+)
+
+var (
+	errInvalidToken = errors.New("invalid or expired link")
+	errMailTooSoon  = errors.New("an email was sent recently, please wait a minute before trying again")
+)
+
+// loadAccountKey reads the key account tokens are signed with, creating it on first start.
+// Unlike session tokens, the links in emails have to keep working across restarts, so a key
+// is only generated when none was saved; any other error is returned rather than replacing it.
+func (g *Game) loadAccountKey() ([]byte, error) {
+	encoded := ""
+	err := g.DbConn.QueryRow("SELECT key FROM server_keys WHERE name = ? LIMIT 1", accountKeyName).Scan(&encoded)
+	if err == nil {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, errors.New("the saved account token key is invalid")
+		}
+
+		return key, nil
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	key := newSessionKey()
+	_, err = g.DbConn.Exec(
+		"INSERT INTO server_keys (name, key, created_at) VALUES (?, ?, ?)",
+		accountKeyName,
+		base64.StdEncoding.EncodeToString(key),
+		time.Now().Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Generated a new account token key.")
+	return key, nil
+}
+
+func (g *Game) signAccountToken(payload string) string {
+	mac := hmac.New(sha256.New, g.accountKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// issueAccountToken creates a signed single-use token for the user, replacing any unused
+// token with the same purpose. Users can only request a new one every AccountMailCooldownSecs.
+func (g *Game) issueAccountToken(userId uint64, purpose TokenPurpose, ttl time.Duration) (string, error) {
+	now := time.Now()
+
+	var lastCreated int64
+	row := g.DbConn.QueryRow(
+		"SELECT COALESCE(MAX(created_at), 0) FROM account_tokens WHERE user_id = ? AND purpose = ? AND used_at = 0",
+		userId,
+		purpose,
+	)
+	row.Scan(&lastCreated)
+
+	if now.Unix()-lastCreated < settings.AccountMailCooldownSecs {
+		return "", errMailTooSoon
+	}
+
+	nonceBytes := make([]byte, 16)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return "", err
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(nonceBytes)
+	expires := now.Add(ttl).Unix()
+
+	_, err := g.DbConn.Exec("DELETE FROM account_tokens WHERE user_id = ? AND purpose = ? AND used_at = 0", userId, purpose)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = g.DbConn.Exec(
+		"INSERT INTO account_tokens (nonce, user_id, purpose, expires_at, created_at) VALUES (?, ?, ?, ?, ?)",
+		nonce,
+		userId,
+		purpose,
+		expires,
+		now.Unix(),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	payload := fmt.Sprintf("%s:%d:%s:%d", purpose, userId, nonce, expires)
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+
+	return encoded + "." + g.signAccountToken(encoded), nil
+}
+
+// parseAccountToken checks the token signature, purpose and expiry, and returns the user and nonce.
+func (g *Game) parseAccountToken(token string, purpose TokenPurpose) (uint64, string, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return 0, "", errInvalidToken
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(g.signAccountToken(encoded))) {
+		return 0, "", errInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, "", errInvalidToken
+	}
+
+	parts := strings.Split(string(payload), ":")
+	if len(parts) != 4 || TokenPurpose(parts[0]) != purpose {
+		return 0, "", errInvalidToken
+	}
+
+	userId, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, "", errInvalidToken
+	}
+
+	expires, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil || expires < time.Now().Unix() {
+		return 0, "", errInvalidToken
+	}
+
+	return userId, parts[2], nil
+}
+
+// consumeAccountToken validates the token and marks it as used. The update only matches
+// unused tokens, so a token cannot be used twice even when two requests race.
+func (g *Game) consumeAccountToken(token string, purpose TokenPurpose) (uint64, error) {
+	userId, nonce, err := g.parseAccountToken(token, purpose)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := g.DbConn.Exec(
+		"UPDATE account_tokens SET used_at = ? WHERE nonce = ? AND user_id = ? AND purpose = ? AND used_at = 0",
+		time.Now().Unix(),
+		nonce,
+		userId,
+		purpose,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil || affected != 1 {
+		return 0, errInvalidToken
+	}
+
+	return userId, nil
+}
+
+// sendMail sends in the background, so requests do not wait on the mail server.
+func (g *Game) sendMail(to string, subject string, body string) {
+	go func() {
+		if err := g.Mailer.Send(to, subject, body); err != nil {
+			logger.Logger.Error(fmt.Sprintf("Failed to send \"%s\" email: %s", subject, err))
+		}
+	}()
+}
+
+// SendVerificationEmail emails the user a link to verify their account.
+func (g *Game) SendVerificationEmail(userId uint64, email string) error {
+	token, err := g.issueAccountToken(userId, TokenVerify, settings.VerifyTokenHours*time.Hour)
+	if err != nil {
+		return err
+	}
+
+	g.sendMail(email, "Verify your Street Wars Inc. account", strings.Join([]string{
+		"Welcome to Street Wars Inc.!",
+		"",
+		"Verify your email to unlock global chat, private messages, bank transfers and gangs:",
+		fmt.Sprintf("%s/verify?token=%s", g.PublicURL, token),
+		"",
+		fmt.Sprintf("The link expires in %d hours.", settings.VerifyTokenHours),
+	}, "\n"))
+
+	return nil
+}
+
+// SendPasswordResetEmail emails the user a link to choose a new password.
+func (g *Game) SendPasswordResetEmail(userId uint64, email string) error {
+	token, err := g.issueAccountToken(userId, TokenResetPassword, settings.ResetTokenMinutes*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	g.sendMail(email, "Reset your Street Wars Inc. password", strings.Join([]string{
+		"Someone asked to reset the password for your Street Wars Inc. account.",
+		"",
+		"Choose a new password here:",
+		fmt.Sprintf("%s/reset-password?token=%s", g.PublicURL, token),
+		"",
+		fmt.Sprintf("The link expires in %d minutes. If you did not ask for this, you can ignore this email.", settings.ResetTokenMinutes),
+	}, "\n"))
+
+	return nil
+}
+
+// markVerified lifts the unverified limits for clients of the user which are already connected.
+func (g *Game) markVerified(userId uint64) {
+	g.mu.Lock()
+	clients := []*Client{}
+	for c := range g.Clients {
+		if c.UserId == userId {
+			clients = append(clients, c)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, c := range clients {
+		c.Mu.Lock()
+		c.Verified = true
+		c.Mu.Unlock()
+	}
+}
@@ -0,0 +1,125 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func newAccountTestGame(t *testing.T) *Game {
+	g := newLedgerTestGame(t)
+
+	key, err := g.loadAccountKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.accountKey = key
+
+	return g
+}
+
+func TestAccountTokenConsumeOnce(t *testing.T) {
+	g := newAccountTestGame(t)
+
+	token, err := g.issueAccountToken(1, TokenVerify, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.consumeAccountToken(token, TokenResetPassword); err != errInvalidToken {
+		t.Errorf("token used for another purpose returned %v", err)
+	}
+
+	userId, err := g.consumeAccountToken(token, TokenVerify)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if userId != 1 {
+		t.Errorf("token was for user %d, want 1", userId)
+	}
+
+	if _, err := g.consumeAccountToken(token, TokenVerify); err != errInvalidToken {
+		t.Errorf("second use returned %v, want %v", err, errInvalidToken)
+	}
+}
+
+func TestAccountTokenRejected(t *testing.T) {
+	g := newAccountTestGame(t)
+
+	token, err := g.issueAccountToken(1, TokenResetPassword, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := g.issueAccountToken(2, TokenResetPassword, -time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// swap the first character of the payload for a different one
+	tampered := "A" + token[1:]
+	if token[0] == 'A' {
+		tampered = "B" + token[1:]
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "no signature", token: token[:len(token)-44]},
+		{name: "tampered payload", token: tampered},
+		{name: "expired", token: expired},
+		{name: "garbage", token: "not.a-token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.consumeAccountToken(tt.token, TokenResetPassword); err != errInvalidToken {
+				t.Errorf("got %v, want %v", err, errInvalidToken)
+			}
+		})
+	}
+
+	// the rejected attempts do not use up the real token
+	if _, err := g.consumeAccountToken(token, TokenResetPassword); err != nil {
+		t.Errorf("valid token rejected: %v", err)
+	}
+}
+
+func TestAccountTokenReplacedAndKeyKept(t *testing.T) {
+	g := newAccountTestGame(t)
+
+	old, err := g.issueAccountToken(1, TokenVerify, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.issueAccountToken(1, TokenVerify, time.Hour); err != errMailTooSoon {
+		t.Fatalf("second token within the cooldown returned %v", err)
+	}
+
+	// pretend the first token was sent long enough ago
+	if _, err := g.DbConn.Exec("UPDATE account_tokens SET created_at = 0"); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := g.issueAccountToken(1, TokenVerify, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := g.consumeAccountToken(old, TokenVerify); err != errInvalidToken {
+		t.Errorf("replaced token returned %v, want %v", err, errInvalidToken)
+	}
+
+	// a restart loads the saved key, so links already sent keep working
+	key, err := g.loadAccountKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.accountKey = key
+
+	if _, err := g.consumeAccountToken(token, TokenVerify); err != nil {
+		t.Errorf("token rejected after reloading the key: %v", err)
+	}
+}
@@ -1,9 +1,13 @@
 package game
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"html/template"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/mreliasen/swi-server/internal"
@@ -31,6 +35,29 @@ type RegistrationResponse struct {
 	Fields  []string `json:"fields"`
 }
 
+type ResetPasswordBody struct {
+	Email    string
+	Token    string
+	Password string
+}
+
+type AccountResponse struct {
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+}
+
+func writeAccountResponse(w http.ResponseWriter, failed bool, message string) {
+	w.Header().Set("Content-Type", "application/json")
+
+	err := json.NewEncoder(w).Encode(AccountResponse{
+		Error:   failed,
+		Message: message,
+	})
+	if err != nil {
+		logger.Logger.Warn(err.Error())
+	}
+}
+
 func (g *Game) CheckNameTaken(w http.ResponseWriter, r *http.Request) {
 	reqBody, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -174,7 +201,8 @@ func (g *Game) HandleRegistration(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if _, err := result.LastInsertId(); err != nil {
+	userId, err := result.LastInsertId()
+	if err != nil {
 		logger.Logger.Warn(err.Error())
 		err = json.NewEncoder(w).Encode(RegistrationResponse{
 			Error:   true,
@@ -187,11 +215,166 @@ func (g *Game) HandleRegistration(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := g.SendVerificationEmail(uint64(userId), body.Email); err != nil {
+		logger.Logger.Warn(err.Error())
+	}
+
 	err = json.NewEncoder(w).Encode(RegistrationResponse{
 		Error:   false,
-		Message: "Account created! ",
+		Message: "Account created! Check your email to verify your account.",
 	})
 	if err != nil {
 		logger.Logger.Warn(err.Error())
 	}
 }
+
+// verifyPage is shown when the link from the verification email is opened in a browser.
+var verifyPage = template.Must(template.New("verify").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Street Wars Inc. - {{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Message}}</p>
+</body>
+</html>`))
+
+func writeVerifyPage(w http.ResponseWriter, failed bool, message string) {
+	title := "Email verified"
+	if failed {
+		w.WriteHeader(http.StatusBadRequest)
+		title = "Something went wrong"
+	}
+
+	err := verifyPage.Execute(w, struct {
+		Title   string
+		Message string
+	}{title, message})
+	if err != nil {
+		logger.Logger.Warn(err.Error())
+	}
+}
+
+// HandleVerify verifies the account behind the token from the verification email.
+// The link is opened in a browser, so GET requests get a page rather than JSON.
+func (g *Game) HandleVerify(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+
+	respond := writeAccountResponse
+	if r.Method == http.MethodGet {
+		respond = writeVerifyPage
+	}
+
+	userId, err := g.consumeAccountToken(token, TokenVerify)
+	if err != nil {
+		if !errors.Is(err, errInvalidToken) {
+			logger.Logger.Warn(err.Error())
+		}
+		respond(w, true, "This verification link is invalid, expired or has already been used.")
+		return
+	}
+
+	if _, err := g.DbConn.Exec("UPDATE users SET verified = 1 WHERE id = ?", userId); err != nil {
+		logger.Logger.Warn(err.Error())
+		respond(w, true, "Failed to verify your email, try again later.")
+		return
+	}
+
+	g.markVerified(userId)
+	respond(w, false, "Your email has been verified, thanks!")
+}
+
+// resetPasswordForm is shown when the link from the reset email is opened in a browser.
+var resetPasswordForm = template.Must(template.New("reset").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Street Wars Inc. - Reset Password</title></head>
+<body>
+<form method="post" action="/reset-password">
+<input type="hidden" name="token" value="{{.}}">
+<label>New password <input type="password" name="password" minlength="8" required></label>
+<button type="submit">Reset password</button>
+</form>
+</body>
+</html>`))
+
+// HandleResetPassword emails a reset link when given an email, and sets the new password when given a token.
+func (g *Game) HandleResetPassword(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		if err := resetPasswordForm.Execute(w, r.URL.Query().Get("token")); err != nil {
+			logger.Logger.Warn(err.Error())
+		}
+		return
+	}
+
+	reqBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeAccountResponse(w, true, "Invalid Details")
+		return
+	}
+
+	// the client sends JSON, the form from the email link is url encoded
+	body := ResetPasswordBody{}
+	if bytes.HasPrefix(bytes.TrimSpace(reqBody), []byte("{")) {
+		json.Unmarshal(reqBody, &body)
+	} else if form, err := url.ParseQuery(string(reqBody)); err == nil {
+		body.Email = form.Get("email")
+		body.Token = form.Get("token")
+		body.Password = form.Get("password")
+	}
+
+	if body.Token == "" {
+		g.requestPasswordReset(w, body.Email)
+		return
+	}
+
+	if len(body.Password) < 8 {
+		writeAccountResponse(w, true, "Password must be at least 8 character long")
+		return
+	}
+
+	passwordHash, err := internal.HashPassword(body.Password)
+	if err != nil {
+		logger.Logger.Warn(err.Error())
+		writeAccountResponse(w, true, "Failed to reset your password, try again later.")
+		return
+	}
+
+	userId, err := g.consumeAccountToken(body.Token, TokenResetPassword)
+	if err != nil {
+		if !errors.Is(err, errInvalidToken) {
+			logger.Logger.Warn(err.Error())
+		}
+		writeAccountResponse(w, true, "This reset link is invalid, expired or has already been used.")
+		return
+	}
+
+	// following the link proves the email is theirs
+	if _, err := g.DbConn.Exec("UPDATE users SET password = ?, verified = 1 WHERE id = ?", passwordHash, userId); err != nil {
+		logger.Logger.Warn(err.Error())
+		writeAccountResponse(w, true, "Failed to reset your password, try again later.")
+		return
+	}
+
+	g.markVerified(userId)
+
+	writeAccountResponse(w, false, "Your password has been reset, you can now log in.")
+}
+
+// requestPasswordReset responds the same whether or not the email has an account, so it cannot be used to look up emails.
+func (g *Game) requestPasswordReset(w http.ResponseWriter, email string) {
+	if !strings.Contains(email, "@") {
+		writeAccountResponse(w, true, "Invalid email")
+		return
+	}
+
+	user := models.User{}
+	row := g.DbConn.QueryRow("SELECT id, email FROM users WHERE email = ? LIMIT 1", email)
+	row.Scan(&user.Id, &user.Email)
+
+	if user.Id != 0 {
+		if err := g.SendPasswordResetEmail(user.Id, user.Email); err != nil && !errors.Is(err, errMailTooSoon) {
+			logger.Logger.Warn(err.Error())
+		}
+	}
+
+	writeAccountResponse(w, false, "If an account exists for that email, a reset link has been sent to it.")
+}
@@ -32,6 +32,7 @@ var BuildingTemplates = map[BuildingType]BuildingTemplate{
 			"/withdraw",
 			"/deposit",
 			"/transfer",
+			"/stash",
 		},
 	},
 	BuildingTypeBar: {
@@ -45,6 +45,20 @@ func (c *City) RandomiseTravelCost() {
 }
 
 func (c *City) StartCityTimers() {
+	go func() {
+		for {
+			time.Sleep(settings.DroppedItemsSweepSeconds * time.Second)
+			c.SweepDroppedItems()
+		}
+	}()
+
+	go func() {
+		for {
+			time.Sleep(settings.HiddenLootSpawnMinutes * time.Minute)
+			c.SpawnHiddenLoot()
+		}
+	}()
+
 	go func() {
 		for {
 			nextRefresh := rand.Intn(settings.CityDemandUpdateMaxMins) + settings.CityDemandUpdateMinMins
@@ -92,6 +106,50 @@ func (c *City) StartCityTimers() {
 	}()
 }
 
+// SweepDroppedItems clears out items which have been lying on the ground for too long.
+func (c *City) SweepDroppedItems() {
+	expired := time.Now().Unix() - settings.DroppedItemsDecaySeconds
+
+	for _, loc := range c.Grid {
+		decayed := []*Item{}
+
+		loc.mu.Lock()
+		for item := range loc.Items {
+			if item.DroppedAt <= expired {
+				decayed = append(decayed, item)
+			}
+		}
+		loc.mu.Unlock()
+
+		for _, item := range decayed {
+			loc.RemoveItem <- &ItemMoved{
+				Item: item,
+				By:   "decay",
+			}
+		}
+	}
+}
+
+// GroundItems counts the items on the ground and stashed away in the city, and the age of the oldest one in seconds.
+func (c *City) GroundItems() (int, int, int64) {
+	dropped := 0
+	hidden := 0
+	oldest := time.Now().Unix()
+
+	for _, loc := range c.Grid {
+		loc.mu.Lock()
+		dropped += len(loc.Items)
+		hidden += len(loc.HiddenItems)
+
+		for item := range loc.Items {
+			oldest = min(oldest, item.DroppedAt)
+		}
+		loc.mu.Unlock()
+	}
+
+	return dropped, hidden, time.Now().Unix() - oldest
+}
+
 func (c *City) UpdateDrugDemand() {
 	c.Mu.Lock()
 	demand := map[string]float32{}
@@ -139,7 +197,8 @@ func (c *City) RandomLocation() Coordinates {
 	}
 }
 
-func (c *City) Setup() {
+// Setup builds the city grid and populates it, from the snapshot if there is one.
+func (c *City) Setup(snapshot *CitySnapshot) {
 	for n := 0; n <= int(c.Height); n++ {
 		for e := 0; e <= int(c.Height); e++ {
 			loc := CreateLocation(c, n, e)
@@ -147,11 +206,19 @@ func (c *City) Setup() {
 		}
 	}
 
-	// spawn NPCs
+	if snapshot != nil {
+		c.Restore(snapshot)
+	} else {
+		c.RandomiseTravelCost()
+	}
+
+	// spawn NPCs, topping up what was restored
 	for npcType, amount := range c.NpcSpawnList {
-		c.NPCs[npcType] = make(map[*Entity]bool)
+		if c.NPCs[npcType] == nil {
+			c.NPCs[npcType] = make(map[*Entity]bool)
+		}
 
-		for i := uint8(0); i < amount; i++ {
+		for i := len(c.NPCs[npcType]); i < int(amount); i++ {
 			npc := NewNPC(npcType)
 			c.NPCs[npcType][npc] = true
 			coords := c.RandomLocation()
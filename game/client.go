@@ -3,20 +3,45 @@ package game
 import (
 	"fmt"
 	"log"
-	"net/http"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/gorilla/websocket"
 	"github.com/mreliasen/swi-server/game/settings"
 	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/responses"
 	"github.com/pterm/pterm"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/types/known/anypb"
 )
 
+type MessageKind int
+
+const (
+	MessageText  MessageKind = iota // plain text command
+	MessageProto                    // Any wrapped protobuf request
+	MessageJSON                     // protojson encoded Any request
+)
+
+// WriteOptions are the per client capabilities which change how a batch is written.
+type WriteOptions struct {
+	Compress bool // compress the frame with permessage-deflate
+	Framed   bool // prefix each message with its length, instead of separating them with a newline
+}
+
+// Transport carries messages between the server and a client.
+type Transport interface {
+	// Read blocks until the next message from the client.
+	Read() (MessageKind, []byte, error)
+	// Write sends a batch of messages to the client.
+	Write(msgs []protoreflect.ProtoMessage, opts WriteOptions) error
+	// Ping keeps the connection alive, failing if the client is gone.
+	Ping() error
+	// WriteClose tells the client the server is closing the connection.
+	WriteClose() error
+	Close() error
+}
+
 type Client struct {
 	Game          *Game
 	Player        *Entity
@@ -24,34 +49,75 @@ type Client struct {
 	UserId        uint64
 	UUID          string
 	UserType      uint8
+	Verified      bool // the account's email has been verified
 	CombatLogging bool
-	Connection    *websocket.Conn
-	Send          chan protoreflect.ProtoMessage
+	Connection    Transport
+	Send          *SendQueue
+	Limiter       *RateLimiter
 	Mu            sync.Mutex
+	requestId     string // id of the typed request being handled, only touched by the input goroutine
+	resumed       bool   // the player was moved over to a new client with /resume
+	loggedOut     bool
+	features      map[Feature]bool // capabilities turned on by the handshake
+	handshaken    bool
+	featuresMu    sync.Mutex
 }
 
-func (c *Client) SendEvent(msg protoreflect.ProtoMessage) {
-	defer func() {
-		if err := recover(); err != nil {
-			log.Println("Trid to send to closed client channel:", err)
+// Reply answers the request being handled, echoing its request id on responses which carry one.
+// Only the input goroutine may call it, messages sent from anywhere else go through SendEvent.
+func (c *Client) Reply(msg protoreflect.ProtoMessage) {
+	c.replyTo(c.requestId, msg)
+}
+
+// replyTo is Reply for handlers which answer from another goroutine, with the id taken beforehand.
+// Messages can be shared between clients, so the tagged message is a copy.
+func (c *Client) replyTo(id string, msg protoreflect.ProtoMessage) {
+	if id != "" {
+		switch m := msg.(type) {
+		case *responses.Generic:
+			tagged := proto.Clone(m).(*responses.Generic)
+			tagged.RequestId = id
+			msg = tagged
+		case *responses.MerchantMessage:
+			tagged := proto.Clone(m).(*responses.MerchantMessage)
+			tagged.RequestId = id
+			msg = tagged
 		}
-	}()
+	}
+
+	c.SendEvent(msg)
+}
 
-	/* val, err := proto.Marshal(msg)
-	if err != nil {
-		logger.Logger.Error(fmt.Sprintf("Error marshaling: %s", err.Error()))
+func (c *Client) SendEvent(msg protoreflect.ProtoMessage) {
+	if c.Connection == nil {
 		return
-	} */
+	}
+
+	if !c.Send.Push(msg) {
+		c.Send.Close()
+		go c.disconnectSlow()
+	}
+}
+
+// disconnectSlow drops a client which cannot keep up with its messages, the usual logout flow takes it from there.
+func (c *Client) disconnectSlow() {
+	c.Mu.Lock()
+	defer c.Mu.Unlock()
 
 	if c.Connection == nil {
 		return
 	}
 
-	c.Send <- msg
+	depth, peak, dropped := c.Send.Stats()
+	logger.Logger.Warn(fmt.Sprintf("Disconnecting slow client %s (queued: %d, peak: %d, dropped: %d)", c.UUID, depth, peak, dropped))
+	c.Connection.Close()
 }
 
 func (c *Client) handleOutput() {
+	conn := c.Connection
 	ticker := time.NewTicker(settings.PingPeriod)
+	var lastWrite time.Time
+	framed := false
 	defer func() {
 		defer func() {
 			if err := recover(); err != nil {
@@ -76,56 +142,46 @@ func (c *Client) handleOutput() {
 	for {
 		select {
 		// send any mssages to the client which are available
-		case msg, ok := <-c.Send:
+		case <-c.Send.Ready:
 			if c.Connection == nil {
 				return
 			}
 
-			c.Connection.SetWriteDeadline(time.Now().Add(settings.WriteWait))
-			if !ok {
-				c.Connection.WriteMessage(websocket.CloseMessage, []byte{})
-				return
+			// messages arriving within the batch window of the last write, like during fights, wait for
+			// the window to pass so they go out together. Anything after a quiet moment goes out straight away.
+			if wait := time.Until(lastWrite.Add(c.Game.BatchWindow)); wait > 0 {
+				time.Sleep(wait)
 			}
 
-			w, err := c.Connection.NextWriter(websocket.BinaryMessage)
-			if err != nil {
-				return
-			}
-
-			m, err := anypb.New(msg)
-			if err != nil {
-				logger.Logger.Error(err.Error())
-				return
-			}
-
-			wire, err := proto.Marshal(m)
-			if err != nil {
-				logger.Logger.Error(err.Error())
-				return
-			}
+			msgs, closed := c.Send.Drain()
 
-			w.Write(wire)
+			opts := WriteOptions{Compress: c.HasFeature(FeatureCompression), Framed: framed}
 
-			n := len(c.Send)
-			for i := 0; i < n; i++ {
-				w.Write([]byte{'\n'})
+			// frames are length-prefixed from the reply to the handshake on, what was queued before it goes out the old way
+			if !framed && c.Handshaken() {
+				if i := handshakeIndex(msgs); i >= 0 {
+					if i > 0 {
+						if err := conn.Write(msgs[:i], opts); err != nil {
+							return
+						}
+						msgs = msgs[i:]
+					}
 
-				m, err := anypb.New(<-c.Send)
-				if err != nil {
-					logger.Logger.Error(err.Error())
-					continue
+					framed = true
+					opts.Framed = true
 				}
+			}
 
-				wire, err := proto.Marshal(m)
-				if err != nil {
-					logger.Logger.Error(err.Error())
-					continue
+			if len(msgs) > 0 {
+				if err := conn.Write(msgs, opts); err != nil {
+					return
 				}
-
-				w.Write(wire)
+				lastWrite = time.Now()
 			}
 
-			if err := w.Close(); err != nil {
+			// flush what was queued before the close, eg. why the client is disconnected
+			if closed {
+				conn.WriteClose()
 				return
 			}
 
@@ -135,8 +191,7 @@ func (c *Client) handleOutput() {
 				return
 			}
 
-			c.Connection.SetWriteDeadline(time.Now().Add(settings.WriteWait))
-			if err := c.Connection.WriteMessage(websocket.PingMessage, nil); err != nil {
+			if err := conn.Ping(); err != nil {
 				return
 			}
 		}
@@ -144,35 +199,42 @@ func (c *Client) handleOutput() {
 }
 
 func (c *Client) handleInput() {
+	conn := c.Connection
 	defer func() {
 		c.Mu.Lock()
+		// the writer may already have closed the connection
+		if c.Connection == nil {
+			c.Mu.Unlock()
+			return
+		}
+
 		c.Connection.Close()
 		c.Connection = nil
 		c.Mu.Unlock()
 
+		c.Send.Close()
 		c.Game.Logout <- c
 	}()
 
-	c.Connection.SetReadLimit(settings.MaxMessageSize)
-	c.Connection.SetReadDeadline(time.Now().Add(settings.PongWait))
-	c.Connection.SetPongHandler(func(string) error {
-		c.Connection.SetReadDeadline(time.Now().Add(settings.PongWait))
-		return nil
-	})
-
 	for {
 		if c.Connection == nil {
 			return
 		}
 
-		_, msg, err := c.Connection.ReadMessage()
+		kind, msg, err := conn.Read()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				logger.Logger.Warn(fmt.Sprintf("WS Error: %s", err))
-			}
 			break
 		}
 
+		switch kind {
+		case MessageProto:
+			ExecuteRequest(c, msg)
+			continue
+		case MessageJSON:
+			ExecuteJSONRequest(c, msg)
+			continue
+		}
+
 		if c.Player != nil {
 			logger.Logger.Trace(pterm.Sprintf("%s: %s", c.Player.Name, msg))
 		}
@@ -181,33 +243,19 @@ func (c *Client) handleInput() {
 	}
 }
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
-}
-
-func HandleWsClient(g *Game, w http.ResponseWriter, r *http.Request) {
-	conn, err := upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		logger.Logger.Trace(pterm.Sprint(err))
-		return
-	}
-
+// NewClient sets up a client for a new connection and starts reading and writing to it.
+func NewClient(g *Game, conn Transport) *Client {
 	client := &Client{
 		Game:          g,
 		Connection:    conn,
 		UUID:          uuid.New().String(),
 		Authenticated: false,
-		Send:          make(chan protoreflect.ProtoMessage),
+		Send:          NewSendQueue(settings.SendQueueSize),
+		Limiter:       NewRateLimiter(),
+		features:      defaultFeatures(),
 	}
 
-	logger.Logger.Trace("New connection.")
-	time.Sleep(500 * time.Millisecond)
-
 	go client.handleInput()
 	go client.handleOutput()
-	g.SendMOTD(client)
+	return client
 }
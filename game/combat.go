@@ -54,6 +54,10 @@ func (c *CombatAction) Execute() {
 		return
 	}
 
+	if c.Action != CombatActionFlee && c.Attacker.IsPlayer {
+		c.Attacker.Reveal()
+	}
+
 	if c.Action != CombatActionAim && c.Action != CombatActionFlee && c.Attacker.IsPlayer {
 		now := time.Now().UnixMilli()
 		until := c.Attacker.LastAttack + settings.PlayerAttackDelayMs
@@ -174,7 +178,7 @@ func (c *CombatAction) punch() {
 		c.Attacker.Mu.Unlock()
 	}
 
-	if !c.Attacker.SkillAcc.SkillCheck() {
+	if !c.Attacker.Skills.Check("accuracy") {
 		if c.Attacker.IsPlayer {
 			c.Attacker.Client.SendEvent(&responses.Generic{
 				Status:   responses.ResponseStatus_RESPONSE_STATUS_NORMAL,
@@ -290,7 +294,7 @@ func (c *CombatAction) shoot() {
 		weapon.Condition -= cond
 	}
 
-	if !c.Attacker.SkillAcc.SkillCheck() {
+	if !c.Attacker.Skills.Check("accuracy") {
 		if c.Attacker.IsPlayer {
 			c.Attacker.Client.SendEvent(&responses.Generic{
 				Status:   responses.ResponseStatus_RESPONSE_STATUS_NORMAL,
@@ -429,7 +433,7 @@ func (c *CombatAction) strike() {
 		return
 	}
 
-	if !c.Attacker.SkillAcc.SkillCheck() {
+	if !c.Attacker.Skills.Check("accuracy") {
 		if c.Attacker.IsPlayer {
 			c.Attacker.Client.SendEvent(&responses.Generic{
 				Status:   responses.ResponseStatus_RESPONSE_STATUS_NORMAL,
@@ -3,6 +3,7 @@ package game
 import (
 	"strings"
 
+	"github.com/mreliasen/swi-server/game/settings"
 	"github.com/mreliasen/swi-server/internal/responses"
 )
 
@@ -15,6 +16,8 @@ type Command struct {
 	AllowAuthed   bool
 	AllowUnAuthed bool
 	AdminCommand  bool
+	BreaksCover   bool // hidden players are revealed when using the command
+	NeedsVerified bool // accounts which have not verified their email cannot use the command
 	Call          func(c *Client, args []string)
 	Help          func(c *Client)
 }
@@ -25,28 +28,42 @@ func ExecuteCommand(c *Client, msg string) {
 	}
 
 	args := strings.Fields(msg)
-	cmdKey := strings.ToLower(args[0])
-	args = args[1:]
+	if len(args) == 0 {
+		return
+	}
+
+	help := len(args) == 2 && args[1] == "help"
+	runCommand(c, strings.ToLower(args[0]), args[1:], help)
+}
+
+// runCommand looks up and runs the command for both text and typed requests,
+// so the same permission checks apply regardless of how it was sent.
+func runCommand(c *Client, cmdKey string, args []string, help bool) {
 	isUnAuthed := !c.Authenticated
 	isAuthed := c.Authenticated
 	isInGame := c.Player != nil
 	isAdmin := false
-	help := false
 
 	if isInGame {
 		isAdmin = c.Player.IsAdmin
 	}
 
-	if len(args) == 1 {
-		if args[0] == "help" {
-			help = true
-		}
-	}
-
 	if alias, ok := CommandAliases[cmdKey]; ok {
 		cmdKey = alias
 	}
 
+	if settings.RequireHandshake && cmdKey != "/handshake" && !c.Handshaken() {
+		c.Reply(&responses.Generic{
+			Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+			Messages: []string{"Your client is out of date, please update it to keep playing."},
+		})
+		return
+	}
+
+	if !c.allowCommand(cmdKey) {
+		return
+	}
+
 	var cmdToRun *Command
 
 	if command, ok := CommandsList[cmdKey]; ok {
@@ -70,12 +87,27 @@ func ExecuteCommand(c *Client, msg string) {
 				return
 			}
 
+			if cmdToRun.NeedsVerified && !c.Verified {
+				c.Reply(&responses.Generic{
+					Status: responses.ResponseStatus_RESPONSE_STATUS_WARN,
+					Messages: []string{
+						"You need to verify your email before you can do that.",
+						"Check your inbox, or type /verify to get a new link.",
+					},
+				})
+				return
+			}
+
+			if cmdToRun.BreaksCover && isInGame {
+				c.Player.Reveal()
+			}
+
 			cmdToRun.Call(c, args)
 			return
 		}
 	}
 
-	c.SendEvent(&responses.Generic{
+	c.Reply(&responses.Generic{
 		Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 		Messages: []string{
 			"Unknown command",
@@ -1,12 +1,14 @@
 package game
 
 import (
+	"database/sql"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/mreliasen/swi-server/game/settings"
 	"github.com/mreliasen/swi-server/internal"
+	"github.com/mreliasen/swi-server/internal/database"
 	"github.com/mreliasen/swi-server/internal/logger"
 	"github.com/mreliasen/swi-server/internal/responses"
 )
@@ -16,6 +18,7 @@ var BuildingCommandsList = map[string]*Command{
 		Args:        []string{"name"},
 		Description: "Opens the shop's trade menu",
 		AllowInGame: true,
+		BreaksCover: true,
 		Help: func(c *Client) {
 			headings := []string{"Example", "Description"}
 			lines := [][]string{
@@ -23,7 +26,7 @@ var BuildingCommandsList = map[string]*Command{
 				{"/shop <name>", "Will open the first sho beginning with the name."},
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(headings, lines),
 			})
@@ -56,7 +59,7 @@ var BuildingCommandsList = map[string]*Command{
 				building = altBuilding
 
 				if building == nil {
-					c.SendEvent(&responses.Generic{
+					c.Reply(&responses.Generic{
 						Messages: []string{"There are no shops around here by that name"},
 					})
 					return
@@ -73,7 +76,7 @@ var BuildingCommandsList = map[string]*Command{
 		AllowInGame: true,
 		Call: func(c *Client, args []string) {
 			if len(args) == 0 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Missing amount. Try: /drink help"},
 				})
 				return
@@ -81,7 +84,7 @@ var BuildingCommandsList = map[string]*Command{
 
 			amount, err := strconv.ParseInt(args[0], 10, 32)
 			if err != nil || amount < 1 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid amount. Try: /drink help"},
 				})
 				return
@@ -92,24 +95,25 @@ var BuildingCommandsList = map[string]*Command{
 			rep_gain := int64(amount * settings.DrinkRepGain)
 
 			if c.Player.Cash < drink_cost {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"You do not have enough money on you"},
 				})
 				return
 			}
 
 			if c.Player.Health <= health_cost {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Don't be stupid, that will kill you."},
 				})
 				return
 			}
 
 			c.Player.Cash -= drink_cost
+			RecordLedger(LedgerDrink, drink_cost, c.Player.CashAccount(), WorldAccount, "")
 			c.Player.Health -= health_cost
 			c.Player.Reputation += rep_gain
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
 				Messages: []string{fmt.Sprintf("You spend %d on buying drinks and paying for strippers, your reputation increased by %d", drink_cost, rep_gain)},
 			})
@@ -124,40 +128,41 @@ var BuildingCommandsList = map[string]*Command{
 				{"/drink 10", fmt.Sprintf("%d", 10*settings.DrinkHealthCost), fmt.Sprintf("%d", 10*settings.DrinkCost), fmt.Sprintf("%d", 10*settings.DrinkRepGain)},
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(headings, lines),
 			})
 		},
 	},
 	"/transfer": {
-		Args:        []string{"user", "amount"},
-		Description: "Transfer money from your bank account to another.",
-		Example:     "/transfer <User> 100",
-		AllowInGame: true,
+		Args:          []string{"user", "amount"},
+		Description:   "Transfer money from your bank account to another.",
+		Example:       "/transfer <User> 100",
+		AllowInGame:   true,
+		NeedsVerified: true,
 		Call: func(c *Client, args []string) {
 			if c.Player.Hometown != c.Player.Loc.City.ShortName {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"You can only use the bank to withdraw money when you are not in your home city."},
 				})
 				return
 			}
 
-			username := args[0]
-			amount, err := strconv.ParseInt(args[1], 10, 32)
-			if err != nil || amount < 1 {
-				c.SendEvent(&responses.Generic{
-					Messages: []string{"Invalid amount. Try: /transfer <username> <amount>"},
+			if len(args) < 2 {
+				c.Reply(&responses.Generic{
+					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{
+						"Invalid command. The format is:  \"/transfer username amount\"",
+					},
 				})
 				return
 			}
 
-			c.Player.Mu.Lock()
-			defer c.Player.Mu.Unlock()
-
-			if amount > c.Player.Bank {
-				c.SendEvent(&responses.Generic{
-					Messages: []string{"You don't have that much money in your bank account."},
+			username := args[0]
+			amount, err := strconv.ParseInt(args[1], 10, 32)
+			if err != nil || amount < 1 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid amount. Try: /transfer <username> <amount>"},
 				})
 				return
 			}
@@ -165,29 +170,78 @@ var BuildingCommandsList = map[string]*Command{
 			var player *Entity
 			for tc := range c.Game.Clients {
 				if tc.Player == nil {
-					return
+					continue
 				}
 
-				if strings.ToLower(tc.Player.Name) == username {
+				if strings.ToLower(tc.Player.Name) == strings.ToLower(username) {
 					player = tc.Player
 					break
 				}
 			}
 
 			if player == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"We cannot find anyone going by that name."},
 				})
 				return
 			}
 
-			player.Mu.Lock()
-			defer player.Mu.Unlock()
+			if player == c.Player {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You cannot transfer money to yourself."},
+				})
+				return
+			}
+
+			// always lock the lower player id first, so two players sending each other money cannot deadlock
+			first, second := c.Player, player
+			if second.PlayerID < first.PlayerID {
+				first, second = second, first
+			}
+
+			first.Mu.Lock()
+			defer first.Mu.Unlock()
+			second.Mu.Lock()
+			defer second.Mu.Unlock()
+
+			if amount > c.Player.Bank {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You don't have that much money in your bank account."},
+				})
+				return
+			}
 
 			c.Player.Bank -= amount
 			player.Bank += amount
+			RecordLedger(LedgerTransfer, amount, c.Player.BankAccount(), player.BankAccount(), "")
+
+			fromEntries := c.Player.pendingLedger()
+			toEntries := player.pendingLedger()
+
+			err = database.WithTx(c.Game.DbConn, func(tx *sql.Tx) error {
+				if err := c.Player.saveBalances(tx, fromEntries); err != nil {
+					return err
+				}
+
+				return player.saveBalances(tx, toEntries)
+			})
+			if err != nil {
+				c.Player.Bank += amount
+				player.Bank -= amount
+				RecordLedger(LedgerReversal, amount, player.BankAccount(), c.Player.BankAccount(), string(LedgerTransfer))
+
+				logger.Logger.Error(err.Error())
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to transfer the money, system error."},
+				})
+				return
+			}
+
+			c.Player.ledgerWritten(len(fromEntries))
+			player.ledgerWritten(len(toEntries))
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Messages: []string{fmt.Sprintf("You just transferred $%d to %s", amount, player.Name)},
 			})
 
@@ -204,15 +258,20 @@ var BuildingCommandsList = map[string]*Command{
 		Example:     "/deposit 100",
 		AllowInGame: true,
 		Call: func(c *Client, args []string) {
+			if len(args) > 0 && strings.ToLower(args[0]) == "gang" {
+				gangBankTransaction(c, args[1:], true)
+				return
+			}
+
 			if c.Player.Hometown != c.Player.Loc.City.ShortName {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"You can only use the bank to withdraw money when you are not in your home city."},
 				})
 				return
 			}
 
 			if len(args) == 0 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Missing amount. Try: /deposit help"},
 				})
 				return
@@ -220,14 +279,14 @@ var BuildingCommandsList = map[string]*Command{
 
 			amount, err := strconv.ParseInt(args[0], 10, 32)
 			if err != nil || amount < 1 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid amount. Try: /deposit help"},
 				})
 				return
 			}
 
 			if c.Player.Cash < amount {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{fmt.Sprintf("You do not have %d on you", amount)},
 				})
 				return
@@ -236,10 +295,11 @@ var BuildingCommandsList = map[string]*Command{
 			logger.LogMoney(c.Player.Name, "bank-pre-statement", c.Player.Bank, "")
 			c.Player.Cash -= amount
 			c.Player.Bank += amount
+			RecordLedger(LedgerDeposit, amount, c.Player.CashAccount(), c.Player.BankAccount(), "")
 			logger.LogMoney(c.Player.Name, "deposit", amount, "")
 			logger.LogMoney(c.Player.Name, "bank-post-statement", c.Player.Bank, "")
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
 				Messages: []string{fmt.Sprintf("You deposit $%d in your bank account.", amount)},
 			})
@@ -250,9 +310,10 @@ var BuildingCommandsList = map[string]*Command{
 			headings := []string{"Example", ""}
 			lines := [][]string{
 				{"/deposit 100", "Deposits $100"},
+				{"/deposit gang 100", "Deposits $100 in your gang's bank"},
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(headings, lines),
 			})
@@ -264,8 +325,13 @@ var BuildingCommandsList = map[string]*Command{
 		Example:     "/withdraw 100",
 		AllowInGame: true,
 		Call: func(c *Client, args []string) {
+			if len(args) > 0 && strings.ToLower(args[0]) == "gang" {
+				gangBankTransaction(c, args[1:], false)
+				return
+			}
+
 			if len(args) == 0 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Missing amount. Try: /withdraw help"},
 				})
 				return
@@ -273,7 +339,7 @@ var BuildingCommandsList = map[string]*Command{
 
 			argAmount, err := strconv.ParseInt(args[0], 10, 32)
 			if err != nil || argAmount < 1 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid amount. Try: /withdraw help"},
 				})
 				return
@@ -282,7 +348,7 @@ var BuildingCommandsList = map[string]*Command{
 			amount := argAmount
 
 			if c.Player.Bank < amount {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{fmt.Sprintf("You do not have $%d in the bank", amount)},
 				})
 				return
@@ -293,8 +359,9 @@ var BuildingCommandsList = map[string]*Command{
 
 			c.Player.Bank -= amount
 			c.Player.Cash += amount
+			RecordLedger(LedgerWithdraw, amount, c.Player.BankAccount(), c.Player.CashAccount(), "")
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
 				Messages: []string{fmt.Sprintf("You withdraw $%d from your account.", amount)},
 			})
@@ -307,9 +374,106 @@ var BuildingCommandsList = map[string]*Command{
 			headings := []string{"Example", ""}
 			lines := [][]string{
 				{"/withdraw 100", "Withdraws $100"},
+				{"/withdraw gang 100", "Withdraws $100 from your gang's bank, limited by your rank"},
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
+				Ascii:    true,
+				Messages: internal.ToTable(headings, lines),
+			})
+		},
+	},
+	"/stash": {
+		Args:        []string{"put|take", "slot"},
+		Description: "Store or take items from your gang's stash.",
+		Example:     "/stash put 2",
+		AllowInGame: true,
+		Call: func(c *Client, args []string) {
+			gang := c.Player.Gang
+			if gang == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You are not in a gang."},
+				})
+				return
+			}
+
+			if len(args) == 0 {
+				headings := []string{"Slot", "Item", "Condition"}
+				rows := [][]string{}
+
+				gang.Stash.Mu.Lock()
+				for i, item := range gang.Stash.Items {
+					if item == nil {
+						continue
+					}
+
+					rows = append(rows, []string{fmt.Sprintf("%d", i), item.GetName(), strings.TrimSpace(item.GetQualitySuffix())})
+				}
+				gang.Stash.Mu.Unlock()
+
+				if len(rows) == 0 {
+					c.Reply(&responses.Generic{
+						Messages: []string{fmt.Sprintf("The [%s] stash is empty.", gang.Tag)},
+					})
+					return
+				}
+
+				c.Reply(&responses.Generic{
+					Ascii:    true,
+					Messages: internal.ToTable(headings, rows),
+				})
+				return
+			}
+
+			if len(args) < 2 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Missing slot. Try: /stash help"},
+				})
+				return
+			}
+
+			slot, err := strconv.ParseInt(args[1], 10, 32)
+			if err != nil || slot < 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid slot. Try: /stash help"},
+				})
+				return
+			}
+
+			switch strings.ToLower(args[0]) {
+			case "put":
+				err = gang.StashPut(c.Player, int(slot))
+			case "take":
+				err = gang.StashTake(c.Player, int(slot))
+			default:
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /stash help"},
+				})
+				return
+			}
+
+			if err != nil {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{err.Error()},
+				})
+				return
+			}
+
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+				Messages: []string{"The gang stash has been updated."},
+			})
+		},
+		Help: func(c *Client) {
+			headings := []string{"Example", ""}
+			lines := [][]string{
+				{"/stash", "Lists the items in your gang's stash"},
+				{"/stash put 2", "Stores the item in inventory slot 2"},
+				{"/stash take 0", "Takes the item in stash slot 0, limited by your rank"},
+			}
+
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(headings, lines),
 			})
@@ -322,14 +486,14 @@ var BuildingCommandsList = map[string]*Command{
 		AllowInGame: true,
 		Call: func(c *Client, args []string) {
 			if len(args) == 0 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Missing amount. Try: /heal help"},
 				})
 				return
 			}
 
 			if c.Player.Health >= settings.PlayerMaxHealth {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"You do not need any patching up, you are at full health"},
 				})
 				return
@@ -337,7 +501,7 @@ var BuildingCommandsList = map[string]*Command{
 
 			amount, err := strconv.ParseInt(args[0], 10, 32)
 			if err != nil || amount < 1 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid amount. Try: /heal help"},
 				})
 				return
@@ -352,20 +516,21 @@ var BuildingCommandsList = map[string]*Command{
 			healCost := int64(healAmount * settings.HealCostPerPoint)
 
 			if c.Player.Cash < healCost {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{fmt.Sprintf("You do not have enough money. Costs %d per point to heal", settings.HealCostPerPoint)},
 				})
 				return
 			}
 
 			c.Player.Cash -= healCost
+			RecordLedger(LedgerHeal, healCost, c.Player.CashAccount(), WorldAccount, "")
 			c.Player.Health += healAmount
 
 			if c.Player.Health > settings.PlayerMaxHealth {
 				c.Player.Health = settings.PlayerMaxHealth
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
 				Messages: []string{fmt.Sprintf("You pay the doctor %d to patch you up.", healAmount*settings.HealCostPerPoint)},
 			})
@@ -379,7 +544,7 @@ var BuildingCommandsList = map[string]*Command{
 				{"/heal 43", fmt.Sprintf("%d", settings.HealCostPerPoint), fmt.Sprintf("%d", 43*settings.HealCostPerPoint)},
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(headings, lines),
 			})
@@ -392,7 +557,7 @@ var BuildingCommandsList = map[string]*Command{
 		AllowInGame: true,
 		Call: func(c *Client, args []string) {
 			if len(args) == 0 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Missing destination. Try: /travel help"},
 				})
 				return
@@ -400,26 +565,27 @@ var BuildingCommandsList = map[string]*Command{
 
 			city, ok := c.Game.World[strings.ToUpper(args[0])]
 			if !ok {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid destination. Try: /travel help"},
 				})
 				return
 			}
 
 			if c.Player.Cash < city.TravelCost {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"You do not have enough cash on you"},
 				})
 				return
 			}
 
 			c.Player.Cash -= city.TravelCost
+			RecordLedger(LedgerTravel, city.TravelCost, c.Player.CashAccount(), WorldAccount, city.ShortName)
 
 			for _, poi := range city.POILocations {
 				if poi.POIType == BuildingTypeAirport {
 					city.Grid[poi.toString()].PlayerJoin <- c
 
-					c.SendEvent(&responses.Generic{
+					c.Reply(&responses.Generic{
 						Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
 						Messages: []string{"You fly off to your destination"},
 					})
@@ -440,10 +606,58 @@ var BuildingCommandsList = map[string]*Command{
 				})
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(headings, lines),
 			})
 		},
 	},
 }
+
+func gangBankTransaction(c *Client, args []string, deposit bool) {
+	gang := c.Player.Gang
+	if gang == nil {
+		c.Reply(&responses.Generic{
+			Messages: []string{"You are not in a gang."},
+		})
+		return
+	}
+
+	if len(args) == 0 {
+		c.Reply(&responses.Generic{
+			Messages: []string{"Missing amount. Try: /deposit gang <amount> or /withdraw gang <amount>"},
+		})
+		return
+	}
+
+	amount, err := strconv.ParseInt(args[0], 10, 32)
+	if err != nil || amount < 1 {
+		c.Reply(&responses.Generic{
+			Messages: []string{"Invalid amount. Try: /deposit gang <amount> or /withdraw gang <amount>"},
+		})
+		return
+	}
+
+	msg := fmt.Sprintf("You deposit $%d in the [%s] bank.", amount, gang.Tag)
+	if deposit {
+		err = gang.Deposit(c.Player, amount)
+	} else {
+		msg = fmt.Sprintf("You withdraw $%d from the [%s] bank.", amount, gang.Tag)
+		err = gang.Withdraw(c.Player, amount)
+	}
+
+	if err != nil {
+		c.Reply(&responses.Generic{
+			Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+			Messages: []string{err.Error()},
+		})
+		return
+	}
+
+	c.Reply(&responses.Generic{
+		Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+		Messages: []string{msg},
+	})
+
+	go c.Player.PlayerSendStatsUpdate()
+}
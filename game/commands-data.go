@@ -2,8 +2,11 @@ package game
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -28,42 +31,320 @@ var CommandAliases = map[string]string{
 }
 
 var CommandsList = map[string]*Command{
-	/* "/track": {
-			Args:        []string{"player-name"},
-			Description: "Try to track the location of a player.",
-			AllowInGame: true,
-			Help: func(c *Client) {
-			},
-			Call: func(c *Client, args []string) {
-				if c.Player.Loc == nil {
-					return
-				}
+	"/track": {
+		Args:        []string{"player-name"},
+		Description: "Try to track the location of a player.",
+		Example:     "/track Tony",
+		AllowInGame: true,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, args []string) {
+			if c.Player.Loc == nil {
+				return
+			}
+
+			if len(args) == 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /track <name>"},
+				})
+				return
+			}
 
-				playername := strings.ToLower(args[0])
+			// look the target up first, so a typo does not use up the cooldown
+			target := c.Game.GetPlayerByName(args[0])
+			if target == nil || target == c.Player || target.Loc == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"There are no one online going by that name."},
+				})
+				return
+			}
 
-				if !c.Player.SkillTrack.SkillCheck() {
-					c.SendEvent(&responses.Generic{
-											Messages: []string{"You failed to track down the location of this player."},
-					})
-					return
+			if !c.Player.SkillReady("track") {
+				return
+			}
+
+			tracked := c.Player.Skills.Check("track")
+
+			if !tracked {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You failed to track down the location of this player."},
+				})
+				return
+			}
+
+			// the target is not doing anything, so their hide skill does not train
+			covered := target.Skills.Roll("hide")
+
+			if covered {
+				c.Reply(&responses.Generic{
+					Messages: []string{fmt.Sprintf("You pick up the trail of %s, but it goes cold. They know how to cover their tracks.", target.Name)},
+				})
+				return
+			}
+
+			city := target.Loc.City
+			coords := target.Loc.Coords
+			radius := int((100 - c.Player.Skills.Value("track")) / 10)
+
+			if radius <= 0 {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+					Messages: []string{fmt.Sprintf("%s was last seen in %s at N%d-E%d.", target.Name, city.Name, coords.North, coords.East)},
+				})
+				return
+			}
+
+			// offset the area, so the target is not always dead center
+			north := coords.North + rand.Intn(radius*2+1) - radius
+			east := coords.East + rand.Intn(radius*2+1) - radius
+
+			c.Reply(&responses.Generic{
+				Status: responses.ResponseStatus_RESPONSE_STATUS_INFO,
+				Messages: []string{fmt.Sprintf(
+					"%s was last seen in %s, somewhere between N%d-N%d and E%d-E%d.",
+					target.Name,
+					city.Name,
+					max(north-radius, 0),
+					min(north+radius, int(city.Height)),
+					max(east-radius, 0),
+					min(east+radius, int(city.Width)),
+				)},
+			})
+		},
+	},
+	"/hide": {
+		Args:        []string{},
+		Description: "Try to hide in the shadows. Attacking, talking or trading gives you away.",
+		Example:     "/hide",
+		AllowInGame: true,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, _ []string) {
+			if c.Player.Loc == nil {
+				return
+			}
+
+			if c.Player.Hidden {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You are already hidden."},
+				})
+				return
+			}
+
+			if len(c.Player.TargetedBy) > 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You cannot hide while someone has you in their sights."},
+				})
+				return
+			}
+
+			if !c.Player.SkillReady("hide") {
+				return
+			}
+
+			hidden := c.Player.Skills.Check("hide")
+
+			if !hidden {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You look for a place to hide, but there is nowhere you would not be seen."},
+				})
+				return
+			}
+
+			c.Player.Hide()
+
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+				Messages: []string{"You slip into the shadows, no one can see you."},
+			})
+		},
+	},
+	"/search": {
+		Args:        []string{},
+		Description: "Search the area for hidden players and stashed items.",
+		Example:     "/search",
+		AllowInGame: true,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, _ []string) {
+			if c.Player.Loc == nil {
+				return
+			}
+
+			if !c.Player.SkillReady("search") {
+				return
+			}
+
+			success := c.Player.Skills.Check("search")
+
+			if !success {
+				wait := c.Player.SkillCooldown("search", settings.SearchFailDelayMs)
+				c.Reply(&responses.Generic{
+					Messages: []string{fmt.Sprintf("You search around but fail to notice anything. You need %d seconds to catch your breath.", (wait+999)/1000)},
+				})
+				return
+			}
+
+			found := []string{}
+
+			loc := c.Player.Loc
+			loc.mu.Lock()
+			others := []*Entity{}
+			for client := range loc.Players {
+				others = append(others, client.Player)
+			}
+			loc.mu.Unlock()
+
+			for _, p := range others {
+				if p == nil || p == c.Player || !p.Hidden || c.Player.CanSee(p) {
+					continue
 				}
 
-				var player *Player
-				for p := range c.Game.Players {
-					if strings.ToLower(p.Name) == playername {
-						player = p
+				p.Spot(c.Player)
+				found = append(found, fmt.Sprintf("You spot %s hiding in the shadows.", p.Name))
+
+				p.Client.SendEvent(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+					Messages: []string{fmt.Sprintf("%s spotted you hiding in the shadows!", c.Player.Name)},
+				})
+			}
+
+			if rand.Intn(100) < settings.SearchLootChance {
+				if item := c.Player.Loc.FindHiddenItem(); item != nil {
+					found = append(found, fmt.Sprintf("You find %s stashed away and pull it out.", item.InspectName()))
+					logger.LogItems(c.Player.Name, "search", item.TemplateName, c.Player.Loc.Coords.North, c.Player.Loc.Coords.East, c.Player.Loc.City.ShortName)
+
+					c.Player.Loc.AddItem <- &ItemMoved{
+						Item: item,
+						By:   c.Player.Name,
 					}
 				}
+			}
+
+			if len(found) == 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You search the area thoroughly, but there is nothing here."},
+				})
+				return
+			}
+
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+				Messages: found,
+			})
+
+			go c.Player.sendGameFrame(false)
+		},
+	},
+	"/snoop": {
+		Args:        []string{"player-name"},
+		Description: "Try to snoop through another player's gear and pockets.",
+		Example:     "/snoop Tony",
+		AllowInGame: true,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, args []string) {
+			if c.Player.Loc == nil {
+				return
+			}
+
+			if len(args) == 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /snoop <name>"},
+				})
+				return
+			}
+
+			var target *Entity
+			name := strings.ToLower(args[0])
 
-				chance := 0.0
-	            if c.Player.SkillTrack.Value < 35{
-	            }
+			loc := c.Player.Loc
+			loc.mu.Lock()
+			here := make([]*Client, 0, len(loc.Players))
+			for t := range loc.Players {
+				here = append(here, t)
+			}
+			loc.mu.Unlock()
 
-				c.SendEvent(&responses.Generic{
-					Messages: []string{"%s was last seen in %s"},
+			for _, t := range here {
+				if t.Player != c.Player && strings.HasPrefix(strings.ToLower(t.Player.Name), name) && c.Player.CanSee(t.Player) {
+					target = t.Player
+					break
+				}
+			}
+
+			if target == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"There are no one here going by that name."},
 				})
-			},
-		}, */
+				return
+			}
+
+			if !c.Player.SkillReady("snoop") {
+				return
+			}
+
+			success := c.Player.Skills.Check("snoop")
+			skill := c.Player.Skills.Value("snoop")
+
+			if !success {
+				logger.LogSkill(c.Player.Name, "snoop", skill, false, target.Name, "")
+
+				c.Reply(&responses.Generic{
+					Messages: []string{fmt.Sprintf("%s catches you trying to snoop through their things.", target.Name)},
+				})
+
+				target.Client.SendEvent(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+					Messages: []string{fmt.Sprintf("You catch %s trying to snoop through your things.", c.Player.Name)},
+				})
+				return
+			}
+
+			slots := []struct {
+				Name string
+				Type IType
+			}{
+				{"Gun", ItemTypeGun},
+				{"Ammo", ItemTypeAmmo},
+				{"Melee", ItemTypeMelee},
+				{"Armor", ItemTypeArmor},
+			}
+
+			rows := [][]string{}
+			equipped := []string{}
+
+			target.Inventory.Mu.Lock()
+			for _, slot := range slots {
+				item := target.Inventory.Equipment[slot.Type]
+				if item == nil {
+					rows = append(rows, []string{slot.Name, "-"})
+					continue
+				}
+
+				rows = append(rows, []string{slot.Name, item.InspectName()})
+				equipped = append(equipped, item.TemplateName)
+			}
+			target.Inventory.Mu.Unlock()
+
+			target.Mu.Lock()
+			cash := target.Cash
+			target.Mu.Unlock()
+
+			// the less skilled, the wider the guess
+			margin := float64(100-skill) / 100
+			low := int64(float64(cash) * (1 - margin))
+			high := int64(float64(cash) * (1 + margin))
+			rows = append(rows, []string{"Cash", fmt.Sprintf("$%d - $%d", low, high)})
+
+			logger.LogSkill(c.Player.Name, "snoop", skill, true, target.Name, fmt.Sprintf("%s cash:%d-%d", strings.Join(equipped, "|"), low, high))
+
+			c.Reply(&responses.Generic{
+				Ascii:    true,
+				Messages: internal.ToTable([]string{target.Name, ""}, rows),
+			})
+		},
+	},
 	"/use": {
 		Args:        []string{"ID"},
 		Description: "Uses a given item by ID",
@@ -76,7 +357,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if len(args) != 1 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid command "},
 				})
 				return
@@ -89,6 +370,7 @@ var CommandsList = map[string]*Command{
 		Args:        []string{"merchantID", "inventoryIndex"},
 		Description: "Sells the item at the given inventory index to the given merchant(id)",
 		AllowInGame: true,
+		BreaksCover: true,
 		Help: func(c *Client) {
 		},
 		Call: func(c *Client, args []string) {
@@ -97,7 +379,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if len(args) < 2 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid command, try /shop <id> <inventory index>"},
 				})
 				return
@@ -106,7 +388,7 @@ var CommandsList = map[string]*Command{
 			buildingId := args[0]
 			index, err := strconv.ParseInt(args[1], 10, 32)
 			if err != nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid sell index."},
 				})
 				return
@@ -120,7 +402,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if building == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid building."},
 				})
 				return
@@ -136,7 +418,7 @@ var CommandsList = map[string]*Command{
 
 			amount, ok := building.ShopBuyType[item.GetItemType()]
 			if !ok {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "I am not interested in that..",
 				})
@@ -144,7 +426,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if amount == 0 {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "I am not looking to buy more of that type of item.",
 				})
@@ -165,6 +447,7 @@ var CommandsList = map[string]*Command{
 
 			c.Player.Mu.Lock()
 			c.Player.Cash += money
+			RecordLedger(LedgerSell, money, WorldAccount, c.Player.CashAccount(), itemEvent.Item.TemplateName)
 			c.Player.Mu.Unlock()
 
 			if amount != -1 {
@@ -177,7 +460,7 @@ var CommandsList = map[string]*Command{
 			c.Player.PlayerSendInventoryUpdate()
 			c.Player.PlayerSendStatsUpdate()
 
-			c.SendEvent(&responses.MerchantMessage{
+			c.Reply(&responses.MerchantMessage{
 				Status:  responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
 				Message: fmt.Sprintf("Here's $%d for the %s", money, itemEvent.Item.GetName()),
 			})
@@ -187,11 +470,12 @@ var CommandsList = map[string]*Command{
 		Args:        []string{"shop type", "inventory index"},
 		Description: "Buys the item at the given inventory index from the given shop",
 		AllowInGame: true,
+		BreaksCover: true,
 		Help: func(c *Client) {
 		},
 		Call: func(c *Client, args []string) {
 			if len(args) < 2 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid command, try /shop <id> <inventory index>"},
 				})
 				return
@@ -200,7 +484,7 @@ var CommandsList = map[string]*Command{
 			buildingId := args[0]
 			index, err := strconv.ParseInt(args[1], 10, 32)
 			if err != nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid sell index."},
 				})
 				return
@@ -214,21 +498,21 @@ var CommandsList = map[string]*Command{
 			}
 
 			if building == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid building."},
 				})
 				return
 			}
 
 			if building.ShopStock == nil || len(building.ShopStock) <= 0 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid shop."},
 				})
 				return
 			}
 
 			if index < 0 || int(index) >= len(building.ShopStock) {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Invalid index."},
 				})
 				return
@@ -237,7 +521,7 @@ var CommandsList = map[string]*Command{
 			itemToBuy := building.ShopStock[index]
 
 			if itemToBuy.Amount == 0 {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "I have none of those left, pick something else.",
 				})
@@ -247,7 +531,7 @@ var CommandsList = map[string]*Command{
 			itemTemplate := ItemsList[itemToBuy.TemplateId]
 
 			if itemTemplate.GetMinRep() > c.Player.Reputation {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "I don't know you well enough to sell you that. Come back when your name is more known.",
 				})
@@ -258,7 +542,7 @@ var CommandsList = map[string]*Command{
 			defer c.Player.Mu.Unlock()
 
 			if int64(itemTemplate.GetPrice()) > c.Player.Cash {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "You do not have enough money on you, come back when you have cash.",
 				})
@@ -266,7 +550,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if !c.Player.Inventory.HasRoom() {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "You don't have enough room to buy that.",
 				})
@@ -275,7 +559,7 @@ var CommandsList = map[string]*Command{
 
 			newItem, ok := NewItem(itemToBuy.TemplateId)
 			if !ok {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "I can't seem to find that item.. odd. Come back later (Error)",
 				})
@@ -283,9 +567,10 @@ var CommandsList = map[string]*Command{
 			}
 
 			c.Player.Cash -= int64(itemTemplate.GetPrice())
+			RecordLedger(LedgerBuy, int64(itemTemplate.GetPrice()), c.Player.CashAccount(), WorldAccount, itemTemplate.TemplateName)
 			err = c.Player.Inventory.addItem(newItem)
 			if err != nil {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "You don't seem to have room, I've dropped the item on the ground (Inventory error)",
 				})
@@ -299,7 +584,7 @@ var CommandsList = map[string]*Command{
 
 			logger.LogBuySell(c.Player.Name, "buy", int64(itemTemplate.GetPrice()), itemTemplate.TemplateName)
 
-			c.SendEvent(&responses.MerchantMessage{
+			c.Reply(&responses.MerchantMessage{
 				Status:  responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
 				Message: fmt.Sprintf("Done. Here's your %s.", newItem.GetName()),
 			})
@@ -313,6 +598,7 @@ var CommandsList = map[string]*Command{
 		Args:        []string{"merchant id", "inventory index"},
 		Description: "Sells the item at the given inventory index to the given merchant(id)",
 		AllowInGame: true,
+		BreaksCover: true,
 		Help: func(c *Client) {
 		},
 		Call: func(c *Client, args []string) {
@@ -339,7 +625,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if druggie == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{"There is no one here to tell to."},
 				})
@@ -356,7 +642,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if item.GetItemType() != ItemTypeDrug {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "I am not interested in that..",
 				})
@@ -364,7 +650,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if !druggie.Inventory.HasRoom() {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "I got what I need, go sell your shit to someone else.",
 				})
@@ -383,9 +669,12 @@ var CommandsList = map[string]*Command{
 				price = 1
 			}
 
+			price = c.Player.Loc.TakeTurfCut(c.Player, price)
+
 			c.Player.Mu.Lock()
 			c.Player.Cash += price
 			c.Player.Reputation += settings.DrugRepIncrease
+			RecordLedger(LedgerSell, price, WorldAccount, c.Player.CashAccount(), itemEvent.Item.TemplateName)
 			c.Player.Mu.Unlock()
 
 			logger.LogBuySell(c.Player.Name, "sell", price, itemEvent.Item.TemplateName)
@@ -395,7 +684,7 @@ var CommandsList = map[string]*Command{
 			c.Player.PlayerSendInventoryUpdate()
 			c.Player.PlayerSendStatsUpdate()
 
-			c.SendEvent(&responses.MerchantMessage{
+			c.Reply(&responses.MerchantMessage{
 				Status:  responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
 				Message: fmt.Sprintf("Here's $%d for the %s", price, itemEvent.Item.GetName()),
 			})
@@ -405,6 +694,7 @@ var CommandsList = map[string]*Command{
 		Args:        []string{"merchant id", "inventory index"},
 		Description: "Buys the item at the given inventory index from the given merchant(id)",
 		AllowInGame: true,
+		BreaksCover: true,
 		Help: func(c *Client) {
 		},
 		Call: func(c *Client, args []string) {
@@ -440,7 +730,7 @@ var CommandsList = map[string]*Command{
 			defer c.Player.Inventory.Mu.Unlock()
 
 			if !c.Player.Inventory.HasRoom() {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "There is no room left in your inventory.",
 				})
@@ -461,7 +751,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if c.Player.Cash < price {
-				c.SendEvent(&responses.MerchantMessage{
+				c.Reply(&responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Message: "You do not have enough cash for that.",
 				})
@@ -472,6 +762,7 @@ var CommandsList = map[string]*Command{
 			logger.LogBuySell(c.Player.Name, "buy", price, item.TemplateName)
 			c.Player.Mu.Unlock()
 
+			requestId := c.requestId
 			go func() {
 				c.Player.Mu.Lock()
 				defer c.Player.Mu.Unlock()
@@ -483,12 +774,13 @@ var CommandsList = map[string]*Command{
 				}
 
 				c.Player.Cash -= price
+				RecordLedger(LedgerBuy, price, c.Player.CashAccount(), WorldAccount, itemEvent.Item.TemplateName)
 				c.Player.Inventory.addItem(itemEvent.Item)
 				dealer.SyncDealerInventory(c)
 				c.Player.PlayerSendInventoryUpdate()
 				c.Player.PlayerSendStatsUpdate()
 
-				c.SendEvent(&responses.MerchantMessage{
+				c.replyTo(requestId, &responses.MerchantMessage{
 					Status:  responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
 					Message: fmt.Sprintf("Here is your %s, anything else?", itemEvent.Item.GetName()),
 				})
@@ -522,6 +814,7 @@ var CommandsList = map[string]*Command{
 		Args:        []string{"name"},
 		Description: "Opens drug sellng menu",
 		AllowInGame: true,
+		BreaksCover: true,
 		Help: func(c *Client) {
 		},
 		Call: func(c *Client, args []string) {
@@ -552,7 +845,7 @@ var CommandsList = map[string]*Command{
 				druggie = altDruggie
 
 				if druggie == nil {
-					c.SendEvent(&responses.Generic{
+					c.Reply(&responses.Generic{
 						Messages: []string{"There are no druggies by that name."},
 					})
 					return
@@ -566,6 +859,7 @@ var CommandsList = map[string]*Command{
 		Args:        []string{"name"},
 		Description: "Opens drug dealers buy menu",
 		AllowInGame: true,
+		BreaksCover: true,
 		Help: func(c *Client) {
 		},
 		Call: func(c *Client, args []string) {
@@ -596,7 +890,7 @@ var CommandsList = map[string]*Command{
 				dealer = altDealer
 
 				if dealer == nil {
-					c.SendEvent(&responses.Generic{
+					c.Reply(&responses.Generic{
 						Messages: []string{"There are no drug dealers here going by that name."},
 					})
 					return
@@ -688,7 +982,7 @@ var CommandsList = map[string]*Command{
 				return
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Messages: []string{fmt.Sprintf("You stop taking aim at %s", c.Player.CurrentTarget.Name)},
 			})
 
@@ -719,7 +1013,7 @@ var CommandsList = map[string]*Command{
 			name := strings.ToLower(args[0])
 
 			for t := range c.Player.Loc.Players {
-				if strings.HasPrefix(strings.ToLower(t.Player.Name), name) {
+				if strings.HasPrefix(strings.ToLower(t.Player.Name), name) && c.Player.CanSee(t.Player) {
 					target = t.Player
 					break
 				}
@@ -735,7 +1029,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if target == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"There are no one here going by that name."},
 				})
 				return
@@ -761,7 +1055,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if c.Player.CurrentTarget == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"You have no target, so you throw some punches into the air."},
 				})
 				return
@@ -787,7 +1081,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if c.Player.CurrentTarget == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"You have no target."},
 				})
 				return
@@ -813,7 +1107,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if c.Player.CurrentTarget == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"You have no target."},
 				})
 				return
@@ -838,7 +1132,7 @@ var CommandsList = map[string]*Command{
 			c.Player.AutoAttackEnabled = !c.Player.AutoAttackEnabled
 			c.Player.Mu.Unlock()
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Status: responses.ResponseStatus_RESPONSE_STATUS_INFO,
 				Messages: []string{
 					"Auto Attack, when enabled, will attack an /aim'ed target with the last type of attack used (default: Punch)",
@@ -859,7 +1153,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if len(c.Player.TargetedBy) == 0 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"No one is targeting you, no need to flee"},
 				})
 				return
@@ -880,7 +1174,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if north < 0 || north > int(c.Player.Loc.City.Height) || east < 0 || east > int(c.Player.Loc.City.Width) {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_WARN,
 					Messages: []string{
 						"You cannot move any further in that direction that direction",
@@ -912,7 +1206,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if len(c.Player.Loc.Items) == 0 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"There are no items laying on the ground."},
 				})
 				return
@@ -947,7 +1241,7 @@ var CommandsList = map[string]*Command{
 				}
 
 				if puItem == nil {
-					c.SendEvent(&responses.Generic{
+					c.Reply(&responses.Generic{
 						Messages: []string{"There are no items on the ground beginning with that."},
 					})
 					return
@@ -1059,7 +1353,7 @@ var CommandsList = map[string]*Command{
 				})
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(headings, data),
 			})
@@ -1074,7 +1368,7 @@ var CommandsList = map[string]*Command{
 		Help: func(c *Client) {
 		},
 		Call: func(c *Client, _ []string) {
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: []string{" ", " ", "Here is a list of all common commands:"},
 			})
@@ -1086,21 +1380,27 @@ var CommandsList = map[string]*Command{
 				{"/say", "Send a chat message visible only to those in the same location."},
 				{"/global", "Send a chat message visible to all online players."},
 				{"/pm <name> <msg>", "Send a private chat message to the specified player."},
+				{"/gang", "Create and manage your gang, see /gang help"},
+				{"/gc <msg>", "Send a chat message to the members of your gang."},
 				{"/buy <name>", "Open drug dealer buy menu"},
 				{"/sell <name>", "Open drug addict sell menu"},
 				{"/shop", "Open the shop window (eg. arms dealer)"},
+				{"/track <name>", "Try to track down where a player is, uses your Track skill."},
+				{"/hide", "Hide from other players and NPCs, uses your Hide skill."},
+				{"/search", "Search for hidden players and items, uses your Search skill."},
+				{"/snoop <name>", "Peek at a player's gear and cash, uses your Snoop skill."},
 				{"/aim <name>", "Takes aim at a target, required before you attack"},
 				{"/unaim", "Removes your lock on the target"},
 				{"/flee <dir>", "Escapes from battle, but you loose items."},
 				{"/autoattack", "Toggles auto-attack on/off."},
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(headings, commands),
 			})
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: []string{" ", " ", "This is all the controls:"},
 			})
@@ -1119,12 +1419,12 @@ var CommandsList = map[string]*Command{
 				{"3", "", "Shoot your target with you gun (weapon dmg)"},
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(ctrlHeadings, ctrls),
 			})
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Messages: []string{
 					"Deal drug to gain money and increase your reputation. The more reputation you have, the more things you can buy and do.",
 					"This game is full-loot PvPvE, anything player or NPC carry will be dropped when killed, and any cash on them will go to the killer.",
@@ -1160,16 +1460,17 @@ var CommandsList = map[string]*Command{
 				}
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(headings, commands),
 			})
 		},
 	},
 	"/pm": {
-		Args:        []string{"user", "message"},
-		Description: "Send a private message to a player",
-		AllowInGame: true,
+		Args:          []string{"user", "message"},
+		Description:   "Send a private message to a player",
+		AllowInGame:   true,
+		NeedsVerified: true,
 		Help: func(c *Client) {
 		},
 		Call: func(c *Client, args []string) {
@@ -1192,7 +1493,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if player == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"There are no one online going by that name."},
 				})
 				return
@@ -1214,6 +1515,7 @@ var CommandsList = map[string]*Command{
 		Args:        []string{"message"},
 		Description: "Send a message locally",
 		AllowInGame: true,
+		BreaksCover: true,
 		Help: func(c *Client) {
 		},
 		Call: func(c *Client, args []string) {
@@ -1247,6 +1549,7 @@ var CommandsList = map[string]*Command{
 		Args:          []string{"message"},
 		Description:   "Send a message to the global chat.",
 		AllowInGame:   true,
+		NeedsVerified: true,
 		AllowAuthed:   false,
 		AllowUnAuthed: false,
 		Help: func(c *Client) {
@@ -1272,6 +1575,84 @@ var CommandsList = map[string]*Command{
 			c.Game.GlobalEvents <- &event
 		},
 	},
+	"/gc": {
+		Args:        []string{"message"},
+		Description: "Send a message to your gang.",
+		AllowInGame: true,
+		Help: func(c *Client) {
+			headings := []string{"Example", ""}
+			lines := [][]string{
+				{"/gc meet at the bar", "Sends the message to every member of your gang"},
+				{"", fmt.Sprintf("Members who are offline get the last %d messages when they log in", settings.GangChatBacklog)},
+			}
+
+			c.Reply(&responses.Generic{
+				Ascii:    true,
+				Messages: internal.ToTable(headings, lines),
+			})
+		},
+		Call: func(c *Client, args []string) {
+			if len(args) == 0 {
+				return
+			}
+
+			gang := c.Player.Gang
+			if gang == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You are not in a gang."},
+				})
+				return
+			}
+
+			message := strings.Join(args, " ")
+			logger.LogChat("gang", c.Player.Name, message, gang.Tag)
+
+			msg := []byte(message)
+			msg = bytes.TrimSpace(bytes.ReplaceAll(msg, []byte{'\n'}, []byte{' '}))
+
+			gang.Chat(c.Player, string(msg))
+		},
+	},
+	"/gang": {
+		Args:          []string{"action"},
+		Description:   "Create and manage your gang.",
+		AllowInGame:   true,
+		NeedsVerified: true,
+		Help: func(c *Client) {
+			headings := []string{"Command", "Description"}
+			lines := [][]string{}
+
+			for _, key := range []string{"info", "create", "invite", "accept", "leave", "kick", "promote", "demote", "perms", "war", "surrender", "disband"} {
+				cmd := GangCommandsList[key]
+				lines = append(lines, []string{
+					strings.TrimSpace("/gang " + key + " " + strings.Join(cmd.Args, " ")),
+					cmd.Description,
+				})
+			}
+
+			c.Reply(&responses.Generic{
+				Ascii:    true,
+				Messages: internal.ToTable(headings, lines),
+			})
+		},
+		Call: func(c *Client, args []string) {
+			if len(args) == 0 {
+				GangCommandsList["info"].Call(c, args)
+				return
+			}
+
+			cmd, ok := GangCommandsList[strings.ToLower(args[0])]
+			if !ok {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Unknown gang command. Try: /gang help"},
+				})
+				return
+			}
+
+			cmd.Call(c, args[1:])
+		},
+	},
 	"/refresh": {
 		Args:          []string{},
 		Description:   "Refresh the game frame",
@@ -1312,7 +1693,7 @@ var CommandsList = map[string]*Command{
 					names = append(names, p.Name)
 				}
 
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						fmt.Sprintf("You cannot move while being held up by: %s", strings.Join(names, ",")),
@@ -1343,7 +1724,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if north < 0 || north > int(c.Player.Loc.City.Height) || east < 0 || east > int(c.Player.Loc.City.Width) {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{
 						"You cannot go any further in that direction",
 					},
@@ -1372,7 +1753,7 @@ var CommandsList = map[string]*Command{
 		Call: func(c *Client, args []string) {
 			_, _, err := c.Game.GetUserCharacter(c.UserId)
 			if err == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"You cannot crate a character. You are not logged in",
@@ -1382,7 +1763,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if len(args) < 2 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"Invalid command. The format is:  \"/login username password\"",
@@ -1401,7 +1782,7 @@ var CommandsList = map[string]*Command{
 					list = append(list, c)
 				}
 
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"Invalid City. Your options are:",
@@ -1417,7 +1798,7 @@ var CommandsList = map[string]*Command{
 			row.Scan(&char.Id)
 
 			if char.Id != 0 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"That name is already taken.",
@@ -1470,7 +1851,7 @@ var CommandsList = map[string]*Command{
 				&newChar.CreatedAt,
 			)
 			if err != nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"Failed to create character, system error.",
@@ -1482,7 +1863,7 @@ var CommandsList = map[string]*Command{
 			lastId, err := result.LastInsertId()
 			if err != nil {
 				log.Print(err)
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"Failed to create character, system error.",
@@ -1495,7 +1876,7 @@ var CommandsList = map[string]*Command{
 			player, lastLocation, err := c.Game.GetUserCharacter(newChar.UserId)
 			if err != nil {
 				log.Print(err)
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"Failed load in your character, system error.",
@@ -1517,7 +1898,7 @@ var CommandsList = map[string]*Command{
 		},
 		Call: func(c *Client, args []string) {
 			if len(args) < 2 {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"Invalid command. The format is:  \"/login username password\"",
@@ -1528,9 +1909,9 @@ var CommandsList = map[string]*Command{
 			email := args[0]
 			password := args[1]
 
-			row := c.Game.DbConn.QueryRow("SELECT id, password, user_type FROM users WHERE email = ? LIMIT 1", email)
+			row := c.Game.DbConn.QueryRow("SELECT id, password, user_type, verified FROM users WHERE email = ? LIMIT 1", email)
 			if row == nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"Invalid username and password combination.",
@@ -1540,9 +1921,9 @@ var CommandsList = map[string]*Command{
 			}
 
 			user := models.User{}
-			err := row.Scan(&user.Id, &user.Password, &user.UserType)
+			err := row.Scan(&user.Id, &user.Password, &user.UserType, &user.Verified)
 			if user.Id == 0 || err != nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"Invalid username and password combination.",
@@ -1552,7 +1933,7 @@ var CommandsList = map[string]*Command{
 			}
 
 			if ok, err := internal.CheckPassword(password, user.Password); !ok || err != nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 					Messages: []string{
 						"Invalid username and password combination.",
@@ -1561,13 +1942,31 @@ var CommandsList = map[string]*Command{
 				return
 			}
 
+			// pick the character back up if it is still in-game after a disconnect
+			if player := c.Game.DisconnectedPlayer(user.Id); player != nil {
+				if c.Game.ResumePlayer(c, player) {
+					return
+				}
+			}
+
 			c.Authenticated = true
 			c.UserId = user.Id
 			c.UserType = uint8(user.UserType)
+			c.Verified = user.Verified
+
+			if !user.Verified {
+				c.Reply(&responses.Generic{
+					Status: responses.ResponseStatus_RESPONSE_STATUS_WARN,
+					Messages: []string{
+						"Your email has not been verified yet. Until it is, global chat, private messages, bank transfers and gangs are unavailable.",
+						"Check your inbox, or type /verify to get a new link.",
+					},
+				})
+			}
 
 			player, lastLocation, err := c.Game.GetUserCharacter(user.Id)
 			if err != nil {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Status: responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
 					Messages: []string{
 						"Your have been logged in, however you do not have a character yet.",
@@ -1580,6 +1979,116 @@ var CommandsList = map[string]*Command{
 			c.Game.LoginPlayer(c, player, lastLocation)
 		},
 	},
+	"/handshake": {
+		Args:          []string{"client version", "capabilities..."},
+		Description:   "Tell the server which client version and capabilities you have.",
+		AllowInGame:   true,
+		AllowAuthed:   true,
+		AllowUnAuthed: true,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, args []string) {
+			if len(args) < 1 {
+				c.Reply(&responses.Generic{
+					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{
+						"Invalid command. The format is:  \"/handshake version [capabilities]\"",
+					},
+				})
+				return
+			}
+
+			version, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Invalid client version"},
+				})
+				return
+			}
+
+			c.Handshake(version, args[1:])
+		},
+	},
+	"/resume": {
+		Args:          []string{"token"},
+		Description:   "Resume your session after being disconnected.",
+		AllowInGame:   false,
+		AllowAuthed:   false,
+		AllowUnAuthed: true,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, args []string) {
+			if len(args) < 1 {
+				c.Reply(&responses.Generic{
+					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{
+						"Invalid command. The format is:  \"/resume token\"",
+					},
+				})
+				return
+			}
+
+			if err := c.Game.ResumeSession(c, args[0]); err != nil {
+				c.Reply(&responses.Generic{
+					Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{
+						"Your session could not be resumed, please /login again.",
+					},
+				})
+				return
+			}
+		},
+	},
+	"/verify": {
+		Args:          []string{},
+		Description:   "Send a new link to verify your email.",
+		AllowInGame:   true,
+		AllowAuthed:   true,
+		AllowUnAuthed: false,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, _ []string) {
+			if c.Verified {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+					Messages: []string{"Your email is already verified."},
+				})
+				return
+			}
+
+			email := ""
+			row := c.Game.DbConn.QueryRow("SELECT email FROM users WHERE id = ? LIMIT 1", c.UserId)
+			if err := row.Scan(&email); err != nil {
+				logger.Logger.Warn(err.Error())
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to send the email, try again later."},
+				})
+				return
+			}
+
+			if err := c.Game.SendVerificationEmail(c.UserId, email); err != nil {
+				message := "Failed to send the email, try again later."
+				if errors.Is(err, errMailTooSoon) {
+					message = "An email was sent recently, please wait a minute before asking for another."
+				} else {
+					logger.Logger.Warn(err.Error())
+				}
+
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{message},
+				})
+				return
+			}
+
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+				Messages: []string{fmt.Sprintf("A verification link has been sent to %s.", email)},
+			})
+		},
+	},
 	"/demand": {
 		Args:         []string{},
 		Description:  "update drug demand for the city",
@@ -1613,6 +2122,139 @@ var CommandsList = map[string]*Command{
 			c.Game.Restock()
 		},
 	},
+	"/grounditems": {
+		Args:         []string{},
+		Description:  "Show how many items are lying on the ground in each city",
+		AllowInGame:  true,
+		AdminCommand: true,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, _ []string) {
+			headings := []string{"City", "Dropped", "Stashed", "Oldest"}
+			rows := [][]string{}
+
+			for _, city := range c.Game.World {
+				dropped, hidden, oldest := city.GroundItems()
+				age := "-"
+				if dropped > 0 {
+					age = fmt.Sprintf("%dm", oldest/60)
+				}
+
+				rows = append(rows, []string{
+					city.Name,
+					fmt.Sprintf("%d", dropped),
+					fmt.Sprintf("%d", hidden),
+					age,
+				})
+			}
+
+			sort.Slice(rows, func(i, j int) bool {
+				return rows[i][0] < rows[j][0]
+			})
+
+			c.Reply(&responses.Generic{
+				Ascii:    true,
+				Messages: internal.ToTable(headings, rows),
+			})
+		},
+	},
+	"/queues": {
+		Args:         []string{},
+		Description:  "Show the outbound message queues of connected clients",
+		AllowInGame:  true,
+		AdminCommand: true,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, _ []string) {
+			headings := []string{"Player", "Queued", "Peak", "Dropped"}
+			rows := [][]string{}
+			totalDepth, maxPeak, totalDropped := 0, 0, uint64(0)
+
+			for client := range c.Game.Clients {
+				name := "<Guest>"
+				if client.Player != nil {
+					name = client.Player.Name
+				}
+
+				depth, peak, dropped := client.Send.Stats()
+				totalDepth += depth
+				maxPeak = max(maxPeak, peak)
+				totalDropped += dropped
+
+				rows = append(rows, []string{
+					name,
+					fmt.Sprintf("%d", depth),
+					fmt.Sprintf("%d", peak),
+					fmt.Sprintf("%d", dropped),
+				})
+			}
+
+			sort.Slice(rows, func(i, j int) bool {
+				return rows[i][0] < rows[j][0]
+			})
+
+			rows = append(rows, []string{
+				"Total",
+				fmt.Sprintf("%d", totalDepth),
+				fmt.Sprintf("%d", maxPeak),
+				fmt.Sprintf("%d", totalDropped),
+			})
+
+			c.Reply(&responses.Generic{
+				Ascii:    true,
+				Messages: internal.ToTable(headings, rows),
+			})
+		},
+	},
+	"/reconcile": {
+		Args:         []string{},
+		Description:  "Check every saved balance against the money ledger",
+		AllowInGame:  true,
+		AdminCommand: true,
+		Help: func(c *Client) {
+		},
+		Call: func(c *Client, _ []string) {
+			mismatches, unbalanced, err := c.Game.Reconcile()
+			if err != nil {
+				logger.Logger.Error(err.Error())
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to reconcile the ledger, system error."},
+				})
+				return
+			}
+
+			if len(mismatches) == 0 && unbalanced == 0 {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+					Messages: []string{"All balances match the ledger."},
+				})
+				return
+			}
+
+			headings := []string{"Name", "Account", "Balance", "Ledger", "Difference"}
+			rows := [][]string{}
+
+			for _, m := range mismatches {
+				rows = append(rows, []string{
+					m.Name,
+					m.Account,
+					fmt.Sprintf("%d", m.Balance),
+					fmt.Sprintf("%d", m.Ledger),
+					fmt.Sprintf("%d", m.Balance-m.Ledger),
+				})
+			}
+
+			messages := internal.ToTable(headings, rows)
+			messages = append(messages, fmt.Sprintf("%d balance(s) do not match, %d transaction(s) do not balance out.", len(mismatches), unbalanced))
+
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+				Ascii:    true,
+				Messages: messages,
+			})
+		},
+	},
 	"/additem": {
 		Args:         []string{"itemid"},
 		Description:  "Spawn a new item",
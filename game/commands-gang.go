@@ -0,0 +1,810 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/responses"
+)
+
+var GangCommandsList = map[string]*Command{
+	"info": {
+		Args:        []string{},
+		Description: "Shows information about your gang.",
+		Example:     "/gang info",
+		Call: func(c *Client, _ []string) {
+			gang := c.Player.Gang
+			if gang == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You are not in a gang. Start one with /gang create <name> <tag>"},
+				})
+				return
+			}
+
+			online := map[uint64]bool{}
+			for _, p := range gang.OnlineMembers() {
+				online[p.PlayerID] = true
+			}
+
+			headings := []string{"Member", "Role", "Online"}
+			rows := [][]string{}
+
+			for _, m := range gang.Members() {
+				status := "No"
+				if online[m.CharacterID] {
+					status = "Yes"
+				}
+
+				rows = append(rows, []string{m.Name, GangRoleNames[m.Role], status})
+			}
+
+			c.Reply(&responses.Generic{
+				Ascii:    true,
+				Messages: []string{fmt.Sprintf("[%s] %s - Bank: $%d - Turf: %d locations", gang.Tag, gang.Name, gang.Bank, len(gang.Turf()))},
+			})
+
+			c.Reply(&responses.Generic{
+				Ascii:    true,
+				Messages: internal.ToTable(headings, rows),
+			})
+		},
+	},
+	"create": {
+		Args:        []string{"name", "tag"},
+		Description: fmt.Sprintf("Starts a new gang, costs $%d cash.", settings.GangCreateCost),
+		Example:     "/gang create Southside Kings SSK",
+		Call: func(c *Client, args []string) {
+			if c.Player.Gang != nil {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"You are already in a gang."},
+				})
+				return
+			}
+
+			if len(args) < 2 {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Invalid command. Try: /gang create <name> <tag>"},
+				})
+				return
+			}
+
+			name := strings.Join(args[:len(args)-1], " ")
+			tag := strings.ToUpper(args[len(args)-1])
+
+			if !internal.IsValidGangName(name) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Gang names must be 3-24 characters long, letters, numbers, spaces, _ and - only."},
+				})
+				return
+			}
+
+			if !internal.IsValidGangTag(tag) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Gang tags must be 2-5 characters long, letters and numbers only."},
+				})
+				return
+			}
+
+			// the fee is taken up front, so it cannot be spent while the gang is created
+			c.Player.Mu.Lock()
+			if c.Player.Cash < settings.GangCreateCost {
+				c.Player.Mu.Unlock()
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{fmt.Sprintf("You need $%d cash on you to start a gang.", settings.GangCreateCost)},
+				})
+				return
+			}
+			c.Player.Cash -= settings.GangCreateCost
+			c.Player.Mu.Unlock()
+
+			refund := func() {
+				c.Player.Mu.Lock()
+				c.Player.Cash += settings.GangCreateCost
+				c.Player.Mu.Unlock()
+			}
+
+			gang, err := c.Game.CreateGang(c.Player, name, tag)
+			if err != nil {
+				refund()
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{err.Error()},
+				})
+				return
+			}
+
+			if !c.Player.SetGang(gang, GangRoleLeader) {
+				gang.Disband()
+				refund()
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to create gang, system error."},
+				})
+				return
+			}
+
+			c.Player.Mu.Lock()
+			RecordLedger(LedgerGangCreate, settings.GangCreateCost, c.Player.CashAccount(), WorldAccount, gang.Tag)
+			c.Player.Mu.Unlock()
+
+			logger.LogMoney(c.Player.Name, "gang-create", settings.GangCreateCost, gang.Tag)
+
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+				Messages: []string{fmt.Sprintf("You founded [%s] %s. Invite members with /gang invite <name>", gang.Tag, gang.Name)},
+			})
+
+			go c.Player.PlayerSendStatsUpdate()
+		},
+	},
+	"invite": {
+		Args:        []string{"player"},
+		Description: "Invites an online player to your gang.",
+		Example:     "/gang invite Tony",
+		Call: func(c *Client, args []string) {
+			gang := c.Player.Gang
+			if gang == nil || !gang.Can(c.Player, GangPermInvite) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"You are not allowed to invite new members."},
+				})
+				return
+			}
+
+			if len(args) == 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /gang invite <name>"},
+				})
+				return
+			}
+
+			player := c.Game.GetPlayerByName(args[0])
+			if player == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"There are no one online going by that name."},
+				})
+				return
+			}
+
+			if player.Gang != nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{fmt.Sprintf("%s is already in a gang.", player.Name)},
+				})
+				return
+			}
+
+			if len(gang.Members()) >= settings.GangMaxMembers {
+				c.Reply(&responses.Generic{
+					Messages: []string{fmt.Sprintf("Your gang is full, the limit is %d members.", settings.GangMaxMembers)},
+				})
+				return
+			}
+
+			player.Mu.Lock()
+			player.GangInvite = gang
+			player.Mu.Unlock()
+
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+				Messages: []string{fmt.Sprintf("You invited %s to join [%s].", player.Name, gang.Tag)},
+			})
+
+			player.Client.SendEvent(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+				Messages: []string{fmt.Sprintf("%s invited you to join [%s] %s. Type /gang accept to join.", c.Player.Name, gang.Tag, gang.Name)},
+			})
+		},
+	},
+	"accept": {
+		Args:        []string{},
+		Description: "Accepts your latest gang invitation.",
+		Example:     "/gang accept",
+		Call: func(c *Client, _ []string) {
+			gang := c.Player.GangInvite
+			if gang == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You have no pending gang invitations."},
+				})
+				return
+			}
+
+			if c.Player.Gang != nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You are already in a gang, /gang leave first."},
+				})
+				return
+			}
+
+			if !c.Game.HasGang(gang.ID) {
+				c.Player.GangInvite = nil
+				c.Reply(&responses.Generic{
+					Messages: []string{"That gang no longer exists."},
+				})
+				return
+			}
+
+			if len(gang.Members()) >= settings.GangMaxMembers {
+				c.Reply(&responses.Generic{
+					Messages: []string{"That gang is full."},
+				})
+				return
+			}
+
+			if !c.Player.SetGang(gang, GangRoleRecruit) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to join gang, system error."},
+				})
+				return
+			}
+
+			gang.Notify(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+				Messages: []string{fmt.Sprintf("%s joined [%s].", c.Player.Name, gang.Tag)},
+			})
+		},
+	},
+	"leave": {
+		Args:        []string{},
+		Description: "Leaves your current gang.",
+		Example:     "/gang leave",
+		Call: func(c *Client, _ []string) {
+			gang := c.Player.Gang
+			if gang == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You are not in a gang."},
+				})
+				return
+			}
+
+			if gang.IsLeader(c.Player) {
+				if len(gang.Members()) > 1 {
+					c.Reply(&responses.Generic{
+						Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+						Messages: []string{"You lead this gang. Promote someone else with /gang promote <name>, or /gang disband."},
+					})
+					return
+				}
+
+				if !gang.IsEmpty() {
+					c.Reply(&responses.Generic{
+						Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+						Messages: []string{"Empty the gang bank and stash before you leave, or everything in it is lost."},
+					})
+					return
+				}
+
+				if gang.Disband() {
+					c.Reply(&responses.Generic{
+						Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+						Messages: []string{fmt.Sprintf("You were the last member, [%s] is no more.", gang.Tag)},
+					})
+				}
+				return
+			}
+
+			if !c.Player.SetGang(nil, 0) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to leave gang, system error."},
+				})
+				return
+			}
+
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+				Messages: []string{fmt.Sprintf("You left [%s].", gang.Tag)},
+			})
+
+			gang.Notify(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+				Messages: []string{fmt.Sprintf("%s left the gang.", c.Player.Name)},
+			})
+		},
+	},
+	"kick": {
+		Args:        []string{"player"},
+		Description: "Kicks a member out of the gang.",
+		Example:     "/gang kick Tony",
+		Call: func(c *Client, args []string) {
+			gang := c.Player.Gang
+			if gang == nil || !gang.Can(c.Player, GangPermKick) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"You are not allowed to kick members."},
+				})
+				return
+			}
+
+			if len(args) == 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /gang kick <name>"},
+				})
+				return
+			}
+
+			member, ok := gang.GetMember(args[0])
+			if !ok {
+				c.Reply(&responses.Generic{
+					Messages: []string{"There is no one in your gang going by that name."},
+				})
+				return
+			}
+
+			if member.CharacterID == c.Player.PlayerID {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You cannot kick yourself, use /gang leave or /gang disband."},
+				})
+				return
+			}
+
+			if member.Role >= gang.Role(c.Player.PlayerID) {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You can only kick members ranked below you."},
+				})
+				return
+			}
+
+			if !gang.RemoveMember(member) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to kick member, system error."},
+				})
+				return
+			}
+
+			if p := c.Game.GetPlayerClient(member.CharacterID); p != nil {
+				p.Client.SendEvent(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+					Messages: []string{fmt.Sprintf("You have been kicked out of [%s].", gang.Tag)},
+				})
+			}
+
+			gang.Notify(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+				Messages: []string{fmt.Sprintf("%s was kicked out of the gang.", member.Name)},
+			})
+		},
+	},
+	"promote": {
+		Args:        []string{"player"},
+		Description: "Promotes a member one rank. Promoting an officer hands them the leadership.",
+		Example:     "/gang promote Tony",
+		Call: func(c *Client, args []string) {
+			gang := c.Player.Gang
+			if gang == nil || !gang.Can(c.Player, GangPermPromote) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"You are not allowed to promote members."},
+				})
+				return
+			}
+
+			if len(args) == 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /gang promote <name>"},
+				})
+				return
+			}
+
+			member, ok := gang.GetMember(args[0])
+			if !ok || member.CharacterID == c.Player.PlayerID {
+				c.Reply(&responses.Generic{
+					Messages: []string{"There is no one else in your gang going by that name."},
+				})
+				return
+			}
+
+			if member.Role == GangRoleOfficer {
+				if !gang.IsLeader(c.Player) {
+					c.Reply(&responses.Generic{
+						Messages: []string{"Only the gang leader can hand over the leadership."},
+					})
+					return
+				}
+
+				gang.Mu.Lock()
+				gang.LeaderID = member.CharacterID
+				saved := gang.Save()
+				if !saved {
+					gang.LeaderID = c.Player.PlayerID
+				}
+				gang.Mu.Unlock()
+
+				if !saved || !gang.SetRole(member.CharacterID, GangRoleLeader) || !gang.SetRole(c.Player.PlayerID, GangRoleOfficer) {
+					c.Reply(&responses.Generic{
+						Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+						Messages: []string{"Failed to promote member, system error."},
+					})
+					return
+				}
+
+				gang.Notify(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+					Messages: []string{fmt.Sprintf("%s is the new leader of [%s].", member.Name, gang.Tag)},
+				})
+				return
+			}
+
+			role := member.Role + 1
+			if role >= gang.Role(c.Player.PlayerID) {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You cannot promote members to your own rank or above."},
+				})
+				return
+			}
+
+			if !gang.SetRole(member.CharacterID, role) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to promote member, system error."},
+				})
+				return
+			}
+
+			gang.Notify(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+				Messages: []string{fmt.Sprintf("%s was promoted to %s.", member.Name, GangRoleNames[role])},
+			})
+		},
+	},
+	"demote": {
+		Args:        []string{"player"},
+		Description: "Demotes a member one rank.",
+		Example:     "/gang demote Tony",
+		Call: func(c *Client, args []string) {
+			gang := c.Player.Gang
+			if gang == nil || !gang.Can(c.Player, GangPermPromote) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"You are not allowed to demote members."},
+				})
+				return
+			}
+
+			if len(args) == 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /gang demote <name>"},
+				})
+				return
+			}
+
+			member, ok := gang.GetMember(args[0])
+			if !ok || member.CharacterID == c.Player.PlayerID {
+				c.Reply(&responses.Generic{
+					Messages: []string{"There is no one else in your gang going by that name."},
+				})
+				return
+			}
+
+			if member.Role >= gang.Role(c.Player.PlayerID) {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You can only demote members ranked below you."},
+				})
+				return
+			}
+
+			if member.Role == GangRoleRecruit {
+				c.Reply(&responses.Generic{
+					Messages: []string{fmt.Sprintf("%s is already the lowest rank.", member.Name)},
+				})
+				return
+			}
+
+			role := member.Role - 1
+			if !gang.SetRole(member.CharacterID, role) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to demote member, system error."},
+				})
+				return
+			}
+
+			gang.Notify(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+				Messages: []string{fmt.Sprintf("%s was demoted to %s.", member.Name, GangRoleNames[role])},
+			})
+		},
+	},
+	"perms": {
+		Args:        []string{"role", "permission", "on|off"},
+		Description: "Shows or changes what each rank is allowed to do.",
+		Example:     "/gang perms officer war on",
+		Call: func(c *Client, args []string) {
+			gang := c.Player.Gang
+			if gang == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You are not in a gang."},
+				})
+				return
+			}
+
+			if len(args) == 0 {
+				headings := []string{"Role"}
+				for _, p := range GangPermissionNames {
+					headings = append(headings, p.Key)
+				}
+
+				rows := [][]string{}
+				gang.Mu.Lock()
+				for role := GangRoleLeader; role >= GangRoleRecruit; role-- {
+					row := []string{GangRoleNames[role]}
+					for _, p := range GangPermissionNames {
+						allowed := "No"
+						if role == GangRoleLeader || gang.Permissions[role]&p.Perm == p.Perm {
+							allowed = "Yes"
+						}
+						row = append(row, allowed)
+					}
+					rows = append(rows, row)
+				}
+				gang.Mu.Unlock()
+
+				c.Reply(&responses.Generic{
+					Ascii:    true,
+					Messages: internal.ToTable(headings, rows),
+				})
+				return
+			}
+
+			if !gang.IsLeader(c.Player) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Only the gang leader can change permissions."},
+				})
+				return
+			}
+
+			if len(args) < 3 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /gang perms <role> <permission> <on|off>"},
+				})
+				return
+			}
+
+			role, ok := GangRoleByName(args[0])
+			if !ok || role == GangRoleLeader {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid role. Try: officer, member or recruit"},
+				})
+				return
+			}
+
+			perm, ok := GangPermissionByName(args[1])
+			if !ok {
+				keys := []string{}
+				for _, p := range GangPermissionNames {
+					keys = append(keys, p.Key)
+				}
+
+				c.Reply(&responses.Generic{
+					Messages: []string{fmt.Sprintf("Invalid permission. Try: %s", strings.Join(keys, ", "))},
+				})
+				return
+			}
+
+			state := strings.ToLower(args[2])
+			if state != "on" && state != "off" {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /gang perms <role> <permission> <on|off>"},
+				})
+				return
+			}
+
+			gang.Mu.Lock()
+			previous := gang.Permissions[role]
+			if state == "on" {
+				gang.Permissions[role] |= perm
+			} else {
+				gang.Permissions[role] &^= perm
+			}
+			saved := gang.Save()
+			if !saved {
+				gang.Permissions[role] = previous
+			}
+			gang.Mu.Unlock()
+
+			if !saved {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to update permissions, system error."},
+				})
+				return
+			}
+
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+				Messages: []string{fmt.Sprintf("%s permission %s for %ss.", strings.ToLower(args[1]), state, GangRoleNames[role])},
+			})
+		},
+	},
+	"war": {
+		Args:        []string{"tag"},
+		Description: "Declares war on another gang, or lists your wars and their history.",
+		Example:     "/gang war SSK",
+		Call: func(c *Client, args []string) {
+			gang := c.Player.Gang
+			if gang == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"You are not in a gang."},
+				})
+				return
+			}
+
+			if len(args) == 0 {
+				headings := []string{"Enemy", "Score", "Ends/Ended", "Result"}
+				rows := [][]string{}
+
+				for _, w := range gang.ActiveWars() {
+					enemy := w.Enemy(gang)
+					rows = append(rows, []string{
+						fmt.Sprintf("[%s]", enemy.Tag),
+						fmt.Sprintf("%d - %d", w.Score(gang), w.Score(enemy)),
+						time.Unix(w.EndsAt, 0).Format("2006-01-02 15:04"),
+						"Ongoing",
+					})
+				}
+
+				for _, w := range gang.WarHistory(10) {
+					enemy, own, other := w.DefenderTag, w.AttackerScore, w.DefenderScore
+					if w.AttackerId != gang.ID {
+						enemy, own, other = w.AttackerTag, w.DefenderScore, w.AttackerScore
+					}
+
+					result := "Draw"
+					if w.WinnerId == gang.ID {
+						result = "Won"
+					} else if w.WinnerId != 0 {
+						result = "Lost"
+					}
+
+					rows = append(rows, []string{
+						fmt.Sprintf("[%s]", enemy),
+						fmt.Sprintf("%d - %d", own, other),
+						time.Unix(w.EndedAt, 0).Format("2006-01-02 15:04"),
+						fmt.Sprintf("%s (%s)", result, w.Result),
+					})
+				}
+
+				if len(rows) == 0 {
+					c.Reply(&responses.Generic{
+						Messages: []string{"Your gang has never been at war."},
+					})
+					return
+				}
+
+				c.Reply(&responses.Generic{
+					Ascii:    true,
+					Messages: internal.ToTable(headings, rows),
+				})
+				return
+			}
+
+			if !gang.Can(c.Player, GangPermDeclareWar) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"You are not allowed to declare war."},
+				})
+				return
+			}
+
+			enemy, err := c.Game.GetGangByTag(args[0])
+			if err != nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"There is no gang with that tag."},
+				})
+				return
+			}
+
+			if _, err := c.Game.DeclareWar(gang, enemy); err != nil {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{err.Error()},
+				})
+				return
+			}
+
+			msg := &responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+				Messages: []string{fmt.Sprintf("[%s] and [%s] are at war for the next %d hours. Rivals will take aim on sight.", gang.Tag, enemy.Tag, settings.GangWarDurationHours)},
+			}
+
+			gang.Notify(msg)
+			enemy.Notify(msg)
+		},
+	},
+	"surrender": {
+		Args:        []string{"tag"},
+		Description: "Surrenders the war against another gang.",
+		Example:     "/gang surrender SSK",
+		Call: func(c *Client, args []string) {
+			gang := c.Player.Gang
+			if gang == nil || !gang.Can(c.Player, GangPermDeclareWar) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"You are not allowed to surrender on behalf of the gang."},
+				})
+				return
+			}
+
+			if len(args) == 0 {
+				c.Reply(&responses.Generic{
+					Messages: []string{"Invalid command. Try: /gang surrender <tag>"},
+				})
+				return
+			}
+
+			enemy, err := c.Game.GetGangByTag(args[0])
+			if err != nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{"There is no gang with that tag."},
+				})
+				return
+			}
+
+			war := gang.WarWith(enemy)
+			if war == nil {
+				c.Reply(&responses.Generic{
+					Messages: []string{fmt.Sprintf("You are not at war with [%s].", enemy.Tag)},
+				})
+				return
+			}
+
+			c.Game.EndWar(war, enemy, GangWarResultSurrender)
+
+			gang.Notify(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+				Messages: []string{fmt.Sprintf("%s surrendered the war against [%s].", c.Player.Name, enemy.Tag)},
+			})
+		},
+	},
+	"disband": {
+		Args:        []string{},
+		Description: "Disbands the gang for good.",
+		Example:     "/gang disband",
+		Call: func(c *Client, _ []string) {
+			gang := c.Player.Gang
+			if gang == nil || !gang.IsLeader(c.Player) {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Only the gang leader can disband the gang."},
+				})
+				return
+			}
+
+			if !gang.IsEmpty() {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Empty the gang bank and stash before disbanding, or everything in it is lost."},
+				})
+				return
+			}
+
+			members := gang.OnlineMembers()
+
+			if !gang.Disband() {
+				c.Reply(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+					Messages: []string{"Failed to disband gang, system error."},
+				})
+				return
+			}
+
+			for _, p := range members {
+				p.Client.SendEvent(&responses.Generic{
+					Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+					Messages: []string{fmt.Sprintf("[%s] %s has been disbanded.", gang.Tag, gang.Name)},
+				})
+			}
+		},
+	},
+}
@@ -25,12 +25,14 @@ var UseEffectsList = map[string]*ItemUseEffect{
 			var useCost int64 = settings.SmartPhoneCost
 
 			if c.Player.Bank < useCost {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{fmt.Sprintf("This information does not come for free, you don't have the $%d it costs in your bank.", useCost)},
 				})
+				return
 			}
 
 			c.Player.Bank -= useCost
+			RecordLedger(LedgerPhoneFee, useCost, c.Player.BankAccount(), WorldAccount, "")
 
 			ctrlHeadings := []string{"Who", "Last Known Location"}
 			locations := [][]string{}
@@ -57,12 +59,12 @@ var UseEffectsList = map[string]*ItemUseEffect{
 				})
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
 				Messages: []string{fmt.Sprintf("Informant: \"(Phone) Your $%d was received. Alright, here is what I know..\"", useCost)},
 			})
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Ascii:    true,
 				Messages: internal.ToTable(ctrlHeadings, locations),
 			})
@@ -79,7 +81,7 @@ var UseEffectsList = map[string]*ItemUseEffect{
 			var repGain int64 = settings.DrugUseRepGain
 
 			if c.Player.Health <= healthCost {
-				c.SendEvent(&responses.Generic{
+				c.Reply(&responses.Generic{
 					Messages: []string{"Using this would kill you.."},
 				})
 
@@ -99,7 +101,7 @@ var UseEffectsList = map[string]*ItemUseEffect{
 				c.Player.Inventory.drop(slotIndex)
 			}
 
-			c.SendEvent(&responses.Generic{
+			c.Reply(&responses.Generic{
 				Messages: []string{fmt.Sprintf("You use the %s. It didn't do your health any favours. (-%d Health, +%d Rep)", item.GetName(), healthCost, repGain)},
 			})
 
@@ -22,13 +22,16 @@ type Entity struct {
 	Cash          int64
 	Health        int
 	Dead          bool
-	SkillAcc      skills.Accuracy
+	Hidden        bool
+	SpottedBy     map[*Entity]bool // players who found this player while hidden
+	Skills        *skills.Set
 	IsPlayer      bool
 	LastLocation  Coordinates
 	// Player -----
 	PlayerID          uint64
 	Bank              int64
 	Gang              *Gang
+	GangInvite        *Gang
 	Hometown          string
 	LastMove          int64
 	LastSkill         map[string]int64
@@ -42,10 +45,6 @@ type Entity struct {
 	IsAdmin           bool
 	AutoAttackEnabled bool
 	AutoAttackType    ActionType
-	SkillHide         skills.Hide
-	SkillSearch       skills.Search
-	SkillSnoop        skills.Snoop
-	SkillTrack        skills.Track
 	// NPC -----
 	NpcID         string
 	NpcCashReward int64
@@ -57,6 +56,9 @@ type Entity struct {
 	NpcHostiles   map[string]bool
 	// Shopping -----
 	ShoppingWith map[*Entity]int64
+	// Ledger entries not yet written -----
+	ledger   []LedgerEntry
+	ledgerMu sync.Mutex
 }
 
 func (n *Entity) RemoveTargetLock() {
@@ -80,6 +82,17 @@ func (n *Entity) RemoveTargetLock() {
 
 func (n *Entity) Death(killer *Entity) {
 	n.Dead = true
+	n.Hidden = false
+
+	if n.Loc != nil {
+		n.Loc.TurfKill(killer, n)
+	}
+
+	if n.IsPlayer && killer.IsPlayer && n.Gang != nil && killer.Gang != nil {
+		if war := killer.Gang.WarWith(n.Gang); war != nil {
+			war.AddKill(killer, n)
+		}
+	}
 
 	// drop all items
 	n.Inventory.dump(n.Loc)
@@ -100,17 +113,18 @@ func (n *Entity) Death(killer *Entity) {
 	if n.IsPlayer {
 		amount := n.Cash
 		killer.Cash += n.Cash
+		n.Cash = 0
+		RecordLedger(LedgerDeathLoot, amount, n.CashAccount(), killer.CashAccount(), killer.Name)
+
 		n.Cash = 50
+		RecordLedger(LedgerRespawn, n.Cash, WorldAccount, n.CashAccount(), "")
 		n.Health = 50
-		n.SkillAcc.Value *= 0.96
-		n.SkillHide.Value *= 0.96
-		n.SkillSnoop.Value *= 0.96
-		n.SkillTrack.Value *= 0.96
-		n.SkillSearch.Value *= 0.96
+		n.Skills.Scale(0.96)
 
 		logger.LogMoney(n.Name, "death", amount, killer.Name)
 	} else {
 		killer.Cash += n.NpcCashReward
+		RecordLedger(LedgerKillReward, n.NpcCashReward, WorldAccount, killer.CashAccount(), n.NpcTitle)
 	}
 
 	if !n.IsPlayer {
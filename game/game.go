@@ -3,11 +3,14 @@ package game
 import (
 	"database/sql"
 	"fmt"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/mreliasen/swi-server/game/settings"
 	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/mailer"
 	"github.com/mreliasen/swi-server/internal/responses"
 	"github.com/pterm/pterm"
 	"google.golang.org/protobuf/reflect/protoreflect"
@@ -23,6 +26,16 @@ type Game struct {
 	NewsFlash    chan protoreflect.ProtoMessage // news flashes
 	Movement     chan protoreflect.ProtoMessage // player movement events
 	World        map[string]*City               // the game world
+	Gangs        map[uint64]*Gang               // loaded gangs, shared by their members
+	Wars         map[uint64]*GangWar            // active gang wars
+	NextRestock  atomic.Int64                   // when the drug dealers get their next shipment, read by SaveWorld
+	Compression  bool                           // negotiate permessage-deflate with clients which support it
+	BatchWindow  time.Duration                  // how long messages following a write are collected before the next one
+	Mailer       mailer.Mailer                  // sends verification and password reset emails
+	PublicURL    string                         // base url of the server, for links in emails
+	sessionKey   []byte                         // signs session tokens
+	accountKey   []byte                         // signs verification and password reset tokens
+	warMu        sync.Mutex                     // held while a war is started, see startWar
 	mu           sync.Mutex
 }
 
@@ -48,8 +61,30 @@ func (g *Game) SendMOTD(c *Client) {
 	})
 }
 
-func (g *Game) GetPlayerClient(playerId uint64) *Entity {
+// OnlinePlayers returns a copy of the logged in players, to range over without holding g.mu.
+func (g *Game) OnlinePlayers() []*Entity {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	players := make([]*Entity, 0, len(g.Players))
 	for p := range g.Players {
+		players = append(players, p)
+	}
+
+	return players
+}
+
+// HasGang checks if the gang is still loaded, ie. it has not been disbanded.
+func (g *Game) HasGang(gangId uint64) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, ok := g.Gangs[gangId]
+	return ok
+}
+
+func (g *Game) GetPlayerClient(playerId uint64) *Entity {
+	for _, p := range g.OnlinePlayers() {
 		if p.PlayerID == playerId {
 			return p
 		}
@@ -58,6 +93,16 @@ func (g *Game) GetPlayerClient(playerId uint64) *Entity {
 	return nil
 }
 
+func (g *Game) GetPlayerByName(name string) *Entity {
+	for _, p := range g.OnlinePlayers() {
+		if strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+
+	return nil
+}
+
 func (g *Game) LoginPlayer(c *Client, p *Entity, k *Coordinates) {
 	g.mu.Lock()
 	defer g.mu.Unlock()
@@ -74,7 +119,7 @@ func (g *Game) LoginPlayer(c *Client, p *Entity, k *Coordinates) {
 		c.SendEvent(&responses.Generic{
 			Status: responses.ResponseStatus_RESPONSE_STATUS_ERROR,
 			Messages: []string{
-				fmt.Sprintf("This player is already logged into the game. If you disconnected while being aim locked your character stays in-game for %d seconds.", settings.CombatLoggingSecs),
+				fmt.Sprintf("This player is already logged into the game. If you disconnected your character stays in-game for %d seconds, use /resume to get back in.", settings.ResumeGraceSecs),
 			},
 		})
 
@@ -97,6 +142,8 @@ func (g *Game) LoginPlayer(c *Client, p *Entity, k *Coordinates) {
 		Type: responses.SystemType_GAME_READY,
 	})
 
+	g.SendSessionToken(c)
+
 	go p.PlayerSendInventoryUpdate()
 	go p.PlayerSendStatsUpdate()
 	go p.PlayerSendPlayerList()
@@ -123,7 +170,7 @@ func (g *Game) LoginPlayer(c *Client, p *Entity, k *Coordinates) {
 		GangTag:  c.Player.GangTag(),
 	}
 
-	c.Send <- event
+	c.SendEvent(event)
 
 	loc.PlayerJoin <- c
 	g.GlobalEvents <- event
@@ -142,6 +189,10 @@ func (g *Game) LoginPlayer(c *Client, p *Entity, k *Coordinates) {
 			"- You can buy a smart phone at the Pawn Shop once you have enough money, enables GPS.",
 		},
 	})
+
+	if p.Gang != nil {
+		go p.Gang.SendChatBacklog(p)
+	}
 }
 
 func (g *Game) Restock() {
@@ -168,16 +219,18 @@ func (g *Game) Save() {
 			client.Player.Save()
 		}
 	}
+
+	g.SaveWorld()
 }
 
 func (g *Game) Run() {
 	go func() {
 		for {
+			time.Sleep(settings.TravelCostChangeMinutes * time.Minute)
+
 			for _, city := range g.World {
 				city.RandomiseTravelCost()
 			}
-
-			time.Sleep(settings.TravelCostChangeMinutes * time.Minute)
 		}
 	}()
 
@@ -185,14 +238,36 @@ func (g *Game) Run() {
 		for {
 			time.Sleep(settings.AutoSaveMinutes * time.Minute)
 			g.Save()
+			g.PruneGangChat()
+		}
+	}()
+
+	go func() {
+		for {
+			time.Sleep(settings.TurfPresenceTickSecs * time.Second)
+
+			for _, city := range g.World {
+				city.TurfTick()
+			}
 		}
 	}()
 
 	go func() {
 		for {
-			// drug refreshing
+			time.Sleep(settings.GangWarCheckSecs * time.Second)
+			g.CheckWars()
+		}
+	}()
+
+	go func() {
+		for {
+			// drug refreshing, a restart does not bring the next shipment forward
+			if wait := g.NextRestock.Load() - time.Now().Unix(); wait > 0 {
+				time.Sleep(time.Duration(wait) * time.Second)
+			}
+
 			g.Restock()
-			time.Sleep(settings.DrugRestockDelaySeconds * time.Second)
+			g.NextRestock.Store(time.Now().Unix() + settings.DrugRestockDelaySeconds)
 		}
 	}()
 
@@ -210,13 +285,7 @@ func (g *Game) Run() {
 		for {
 			message := <-g.GlobalEvents
 			for client := range g.Clients {
-				select {
-				case client.Send <- message:
-				default:
-					g.mu.Lock()
-					delete(g.Clients, client)
-					g.mu.Unlock()
-				}
+				client.SendEvent(message)
 			}
 		}
 	}()
@@ -225,13 +294,7 @@ func (g *Game) Run() {
 		for {
 			news := <-g.NewsFlash
 			for client := range g.Clients {
-				select {
-				case client.Send <- news:
-				default:
-					g.mu.Lock()
-					delete(g.Clients, client)
-					g.mu.Unlock()
-				}
+				client.SendEvent(news)
 			}
 		}
 	}()
@@ -255,12 +318,28 @@ func (g *Game) Run() {
 				}
 			}
 
+			wait := 0
+			if client.Player != nil {
+				// give the player a chance to /resume before logging them out
+				wait = settings.ResumeGraceSecs
+			}
+
+			if client.CombatLogging {
+				wait = max(wait, settings.CombatLoggingSecs)
+			}
+
 			client.Mu.Unlock()
 
 			go func() {
-				if client.CombatLogging {
-					time.Sleep(settings.CombatLoggingSecs * time.Second)
+				time.Sleep(time.Duration(wait) * time.Second)
+
+				client.Mu.Lock()
+				if client.resumed || client.loggedOut {
+					client.Mu.Unlock()
+					return
 				}
+				client.loggedOut = true
+				client.Mu.Unlock()
 
 				g.HandleLogout(client)
 			}()
@@ -321,7 +400,7 @@ func (g *Game) RenderConsoleUI() {
 
 	for {
 		list := [][]string{
-			{"Player", "Location", "Coords"},
+			{"Player", "Location", "Coords", "Queued", "Dropped"},
 		}
 
 		playersOnline := len(g.Clients)
@@ -346,10 +425,13 @@ func (g *Game) RenderConsoleUI() {
 					name = "(CL) " + name
 				}
 
+				depth, _, dropped := client.Send.Stats()
 				list = append(list, []string{
 					name,
 					city,
 					coords,
+					fmt.Sprintf("%d", depth),
+					fmt.Sprintf("%d", dropped),
 				})
 			}
 		}
@@ -394,19 +476,38 @@ func NewGame(db *sql.DB) *Game {
 		GlobalEvents: make(chan protoreflect.ProtoMessage),
 		NewsFlash:    make(chan protoreflect.ProtoMessage),
 		World:        cityList,
+		Gangs:        make(map[uint64]*Gang),
+		Wars:         make(map[uint64]*GangWar),
+		BatchWindow:  settings.BatchWindowMs * time.Millisecond,
+		Mailer:       mailer.NewFileMailer("logs/mail.log"),
+		sessionKey:   newSessionKey(),
+	}
+
+	accountKey, err := game.loadAccountKey()
+	if err != nil {
+		logger.Logger.Fatal(fmt.Sprintf("Failed to load account token key: %s", err))
+	}
+	game.accountKey = accountKey
+
+	world, snapshots := game.LoadWorld()
+	if world != nil {
+		game.NextRestock.Store(world.NextRestock)
 	}
 
 	p, _ = pterm.DefaultProgressbar.WithTotal(len(game.World)).WithTitle("Populating Cities..").WithRemoveWhenDone().Start()
 
 	for _, city := range game.World {
 		p.UpdateTitle(city.Name)
-		city.Setup()
-		city.StartCityTimers()
 		city.Game = &game
+		city.Setup(snapshots[city.ShortName])
+		city.StartCityTimers()
 		pterm.Success.Println("Done: " + city.Name)
 		p.Increment()
 	}
 
+	game.LoadTurf()
+	game.LoadWars()
+
 	logger.Logger.Info("Setup Complete")
 	return &game
 }
@@ -0,0 +1,316 @@
+package game
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mreliasen/swi-server/internal/database"
+	"github.com/mreliasen/swi-server/internal/database/models"
+	"github.com/mreliasen/swi-server/internal/logger"
+)
+
+// how much each rank can take out of the gang bank and stash per 24 hours, -1 is unlimited
+var (
+	GangCashWithdrawLimits = map[GangRole]int64{
+		GangRoleRecruit: 0,
+		GangRoleMember:  2500,
+		GangRoleOfficer: 25000,
+		GangRoleLeader:  -1,
+	}
+	GangItemWithdrawLimits = map[GangRole]int64{
+		GangRoleRecruit: 0,
+		GangRoleMember:  2,
+		GangRoleOfficer: 10,
+		GangRoleLeader:  -1,
+	}
+)
+
+func (g *Gang) logBankAction(p *Entity, action string, amount int64, item string) {
+	_, err := g.Game.DbConn.Exec(
+		"INSERT INTO gang_bank_log (gang_id, character_id, action, amount, item, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		g.ID,
+		p.PlayerID,
+		action,
+		amount,
+		item,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+}
+
+// withdrawnToday sums up the amount the character took out with the given action in the last 24 hours.
+func (g *Gang) withdrawnToday(characterId uint64, action string) int64 {
+	row := g.Game.DbConn.QueryRow(
+		"SELECT COALESCE(SUM(amount), 0) FROM gang_bank_log WHERE gang_id = ? AND character_id = ? AND action = ? AND created_at > ?",
+		g.ID,
+		characterId,
+		action,
+		time.Now().Add(-24*time.Hour).Unix(),
+	)
+
+	var total int64
+	if err := row.Scan(&total); err != nil {
+		logger.Logger.Error(err.Error())
+	}
+
+	return total
+}
+
+// checkWithdrawLimit expects the caller to hold g.Mu.
+func (g *Gang) checkWithdrawLimit(p *Entity, action string, limits map[GangRole]int64, amount int64) error {
+	if !g.can(p, GangPermWithdraw) {
+		return errors.New("your rank is not allowed to take anything from the gang")
+	}
+
+	limit := limits[g.role(p.PlayerID)]
+	if limit < 0 {
+		return nil
+	}
+
+	left := limit - g.withdrawnToday(p.PlayerID, action)
+	if amount > left {
+		if left <= 0 {
+			return errors.New("you have reached your daily limit, try again later")
+		}
+
+		return fmt.Errorf("your rank can only take out %d more today", left)
+	}
+
+	return nil
+}
+
+func (g *Gang) Deposit(p *Entity, amount int64) error {
+	g.Mu.Lock()
+	defer g.Mu.Unlock()
+
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+
+	if p.Cash < amount {
+		return fmt.Errorf("you do not have $%d on you", amount)
+	}
+
+	p.Cash -= amount
+	g.Bank += amount
+	RecordLedger(LedgerGangDeposit, amount, p.CashAccount(), g.BankAccount(), p.Name)
+
+	if err := g.saveMoney(p); err != nil {
+		p.Cash += amount
+		g.Bank -= amount
+		RecordLedger(LedgerReversal, amount, g.BankAccount(), p.CashAccount(), string(LedgerGangDeposit))
+
+		logger.Logger.Error(err.Error())
+		return errors.New("failed to deposit, system error")
+	}
+
+	g.logBankAction(p, "deposit", amount, "")
+	logger.LogMoney(p.Name, "gang-deposit", amount, g.Tag)
+	logger.LogMoney(g.Tag, "gang-bank-statement", g.Bank, "")
+
+	return nil
+}
+
+func (g *Gang) Withdraw(p *Entity, amount int64) error {
+	g.Mu.Lock()
+	defer g.Mu.Unlock()
+
+	if g.Bank < amount {
+		return fmt.Errorf("the gang does not have $%d in the bank", amount)
+	}
+
+	if err := g.checkWithdrawLimit(p, "withdraw", GangCashWithdrawLimits, amount); err != nil {
+		return err
+	}
+
+	p.Mu.Lock()
+	defer p.Mu.Unlock()
+
+	g.Bank -= amount
+	p.Cash += amount
+	RecordLedger(LedgerGangWithdraw, amount, g.BankAccount(), p.CashAccount(), p.Name)
+
+	if err := g.saveMoney(p); err != nil {
+		g.Bank += amount
+		p.Cash -= amount
+		RecordLedger(LedgerReversal, amount, p.CashAccount(), g.BankAccount(), string(LedgerGangWithdraw))
+
+		logger.Logger.Error(err.Error())
+		return errors.New("failed to withdraw, system error")
+	}
+
+	g.logBankAction(p, "withdraw", amount, "")
+	logger.LogMoney(p.Name, "gang-withdraw", amount, g.Tag)
+	logger.LogMoney(g.Tag, "gang-bank-statement", g.Bank, "")
+
+	return nil
+}
+
+// saveMoney writes the player's and the gang's balances together with their ledger entries,
+// so money is never lost or duplicated. Pass a nil player to only write the gang bank.
+func (g *Gang) saveMoney(p *Entity) error {
+	gangEntries := g.pendingLedger()
+	playerEntries := []LedgerEntry{}
+	if p != nil {
+		playerEntries = p.pendingLedger()
+	}
+
+	err := database.WithTx(g.Game.DbConn, func(tx *sql.Tx) error {
+		if p != nil {
+			if err := p.saveBalances(tx, playerEntries); err != nil {
+				return err
+			}
+		}
+
+		return g.saveBank(tx, gangEntries)
+	})
+	if err != nil {
+		return err
+	}
+
+	g.ledgerWritten(len(gangEntries))
+	if p != nil {
+		p.ledgerWritten(len(playerEntries))
+	}
+
+	return nil
+}
+
+func (g *Gang) loadStash() {
+	g.Stash = NewInventory(nil)
+
+	row := g.Game.DbConn.QueryRow("SELECT inventory FROM gang_stash WHERE gang_id = ? LIMIT 1", g.ID)
+	stashData := models.GangStash{}
+	row.Scan(&stashData.Inventory)
+
+	g.Stash.Mu.Lock()
+	g.Stash.deserialize(stashData.Inventory)
+	g.Stash.Mu.Unlock()
+}
+
+// saveStashMove writes the gang stash and the player's inventory together, so an item moved
+// between them is never lost or duplicated if the server goes down before the next autosave.
+func (g *Gang) saveStashMove(p *Entity) error {
+	g.Stash.Mu.Lock()
+	stash, err := g.Stash.serialize()
+	g.Stash.Mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	p.Inventory.Mu.Lock()
+	inventory, err := p.Inventory.serialize()
+	p.Inventory.Mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return database.WithTx(g.Game.DbConn, func(tx *sql.Tx) error {
+		now := time.Now().Unix()
+
+		_, err := tx.Exec(
+			"INSERT OR REPLACE INTO gang_stash (gang_id, inventory, updated_at) VALUES(?, ?, ?)",
+			g.ID, stash, now,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(
+			"INSERT OR REPLACE INTO inventory (user_id, inventory, updated_at) VALUES(?, ?, ?)",
+			p.Client.UserId, inventory, now,
+		)
+		return err
+	})
+}
+
+// StashPut moves the item in the players inventory slot into the gang stash.
+func (g *Gang) StashPut(p *Entity, slot int) error {
+	g.Mu.Lock()
+	defer g.Mu.Unlock()
+
+	if !g.Stash.HasRoom() {
+		return errors.New("the gang stash is full")
+	}
+
+	item := p.Inventory.remove(slot)
+	if item == nil {
+		return errors.New("there is no item in that inventory slot")
+	}
+
+	if err := g.Stash.addItem(item); err != nil {
+		p.Inventory.addItem(item)
+		return err
+	}
+
+	if err := g.saveStashMove(p); err != nil {
+		logger.Logger.Error(err.Error())
+		g.Stash.remove(g.Stash.slotOf(item))
+		p.Inventory.addItem(item)
+		return errors.New("failed to store the item, system error")
+	}
+
+	g.logBankAction(p, "stash-put", 1, item.TemplateName)
+	logger.LogItems(p.Name, "gang-stash-put", item.TemplateName, p.Loc.Coords.North, p.Loc.Coords.East, p.Loc.City.ShortName)
+
+	go p.PlayerSendInventoryUpdate()
+	return nil
+}
+
+// StashTake moves the item in the stash slot into the players inventory.
+func (g *Gang) StashTake(p *Entity, slot int) error {
+	g.Mu.Lock()
+	defer g.Mu.Unlock()
+
+	if !p.Inventory.HasRoom() {
+		return errors.New("you do not have room for that in your inventory")
+	}
+
+	if err := g.checkWithdrawLimit(p, "stash-take", GangItemWithdrawLimits, 1); err != nil {
+		return err
+	}
+
+	item := g.Stash.remove(slot)
+	if item == nil {
+		return errors.New("there is no item in that stash slot")
+	}
+
+	if err := p.Inventory.addItem(item); err != nil {
+		g.Stash.addItem(item)
+		return err
+	}
+
+	if err := g.saveStashMove(p); err != nil {
+		logger.Logger.Error(err.Error())
+		p.Inventory.remove(p.Inventory.slotOf(item))
+		g.Stash.addItem(item)
+		return errors.New("failed to take the item, system error")
+	}
+
+	g.logBankAction(p, "stash-take", 1, item.TemplateName)
+	logger.LogItems(p.Name, "gang-stash-take", item.TemplateName, p.Loc.Coords.North, p.Loc.Coords.East, p.Loc.City.ShortName)
+
+	go p.PlayerSendInventoryUpdate()
+	return nil
+}
+
+// IsEmpty reports whether the gang has nothing left in the bank or stash.
+func (g *Gang) IsEmpty() bool {
+	if g.Bank > 0 {
+		return false
+	}
+
+	g.Stash.Mu.Lock()
+	defer g.Stash.Mu.Unlock()
+
+	for _, item := range g.Stash.Items {
+		if item != nil {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,483 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/database/models"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/responses"
+)
+
+const (
+	GangWarResultSurrender = "surrender"
+	GangWarResultTimeout   = "timeout"
+	GangWarResultDisband   = "disband"
+)
+
+type GangWar struct {
+	ID            uint64
+	Attacker      *Gang
+	Defender      *Gang
+	AttackerScore int
+	DefenderScore int
+	StartedAt     int64
+	EndsAt        int64
+	Mu            sync.Mutex
+}
+
+// Enemy returns the gang on the other side of the war.
+func (w *GangWar) Enemy(g *Gang) *Gang {
+	if w.Attacker == g {
+		return w.Defender
+	}
+
+	return w.Attacker
+}
+
+func (w *GangWar) Involves(g *Gang) bool {
+	return w.Attacker == g || w.Defender == g
+}
+
+func (w *GangWar) Score(g *Gang) int {
+	if w.Attacker == g {
+		return w.AttackerScore
+	}
+
+	return w.DefenderScore
+}
+
+func (w *GangWar) save() {
+	_, err := w.Attacker.Game.DbConn.Exec(
+		"UPDATE gang_wars SET attacker_score = ?, defender_score = ? WHERE id = ?",
+		w.AttackerScore,
+		w.DefenderScore,
+		w.ID,
+	)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+}
+
+// AddKill scores a kill for the gang and lets both sides know the standing.
+func (w *GangWar) AddKill(killer *Entity, victim *Entity) {
+	w.Mu.Lock()
+	if w.Attacker == killer.Gang {
+		w.AttackerScore += 1
+	} else {
+		w.DefenderScore += 1
+	}
+	w.save()
+	attackerScore, defenderScore := w.AttackerScore, w.DefenderScore
+	w.Mu.Unlock()
+
+	msg := &responses.Generic{
+		Status: responses.ResponseStatus_RESPONSE_STATUS_INFO,
+		Messages: []string{
+			fmt.Sprintf("War: %s [%s] took out %s [%s]. Score [%s] %d - %d [%s]", killer.Name, killer.Gang.Tag, victim.Name, victim.Gang.Tag, w.Attacker.Tag, attackerScore, defenderScore, w.Defender.Tag),
+		},
+	}
+
+	w.Attacker.Notify(msg)
+	w.Defender.Notify(msg)
+}
+
+// WarWith returns the active war between the two gangs, if any.
+func (g *Gang) WarWith(other *Gang) *GangWar {
+	if other == nil || other == g {
+		return nil
+	}
+
+	g.Game.mu.Lock()
+	defer g.Game.mu.Unlock()
+
+	for _, w := range g.Game.Wars {
+		if w.Involves(g) && w.Involves(other) {
+			return w
+		}
+	}
+
+	return nil
+}
+
+// ActiveWars returns all wars the gang is fighting.
+func (g *Gang) ActiveWars() []*GangWar {
+	g.Game.mu.Lock()
+	defer g.Game.mu.Unlock()
+
+	wars := []*GangWar{}
+	for _, w := range g.Game.Wars {
+		if w.Involves(g) {
+			wars = append(wars, w)
+		}
+	}
+
+	return wars
+}
+
+// WarHistory returns the gang's most recent finished wars.
+func (g *Gang) WarHistory(limit int) []models.GangWar {
+	history := []models.GangWar{}
+
+	rows, err := g.Game.DbConn.Query(
+		`SELECT
+            attacker_id,
+            attacker_tag,
+            defender_tag,
+            attacker_score,
+            defender_score,
+            winner_id,
+            result,
+            ended_at
+        FROM
+            gang_wars
+        WHERE
+            (attacker_id = ? OR defender_id = ?)
+            AND ended_at > 0
+        ORDER BY
+            ended_at DESC
+        LIMIT
+            ?`,
+		g.ID,
+		g.ID,
+		limit,
+	)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return history
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		war := models.GangWar{}
+		err := rows.Scan(
+			&war.AttackerId,
+			&war.AttackerTag,
+			&war.DefenderTag,
+			&war.AttackerScore,
+			&war.DefenderScore,
+			&war.WinnerId,
+			&war.Result,
+			&war.EndedAt,
+		)
+		if err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		history = append(history, war)
+	}
+
+	return history
+}
+
+func (g *Game) DeclareWar(attacker *Gang, defender *Gang) (*GangWar, error) {
+	if attacker == defender {
+		return nil, errors.New("you cannot declare war on your own gang")
+	}
+
+	war, err := g.startWar(attacker, defender)
+	if err != nil {
+		return nil, err
+	}
+
+	g.NewsFlash <- &responses.NewsFlash{
+		Msg: fmt.Sprintf("<NEWS FLASH> [%s] %s has declared war on [%s] %s! Expect bloodshed in the streets.", attacker.Tag, attacker.Name, defender.Tag, defender.Name),
+	}
+
+	for _, p := range attacker.OnlineMembers() {
+		go p.aimAtWarEnemies()
+	}
+
+	return war, nil
+}
+
+// startWar checks the gangs can go to war and saves it. Wars are started one at a time, so
+// the checks and the insert cannot interleave with the other gang declaring back, and the
+// gang_wars_active_pair index rejects a second active war between them regardless.
+func (g *Game) startWar(attacker *Gang, defender *Gang) (*GangWar, error) {
+	g.warMu.Lock()
+	defer g.warMu.Unlock()
+
+	if attacker.WarWith(defender) != nil {
+		return nil, fmt.Errorf("you are already at war with [%s]", defender.Tag)
+	}
+
+	now := time.Now()
+
+	// stops gangs from surrendering and declaring again to reset the score
+	var lastEnded int64
+	row := g.DbConn.QueryRow(
+		"SELECT COALESCE(MAX(ended_at), 0) FROM gang_wars WHERE (attacker_id = ? AND defender_id = ?) OR (attacker_id = ? AND defender_id = ?)",
+		attacker.ID,
+		defender.ID,
+		defender.ID,
+		attacker.ID,
+	)
+	if err := row.Scan(&lastEnded); err != nil {
+		logger.Logger.Error(err.Error())
+		return nil, errors.New("failed to declare war, system error")
+	}
+
+	if until := lastEnded + settings.GangWarCooldownHours*3600; until > now.Unix() {
+		return nil, fmt.Errorf("your last war with [%s] ended recently, you can declare war again in %s", defender.Tag, time.Until(time.Unix(until, 0)).Round(time.Minute))
+	}
+	war := &GangWar{
+		Attacker:  attacker,
+		Defender:  defender,
+		StartedAt: now.Unix(),
+		EndsAt:    now.Add(settings.GangWarDurationHours * time.Hour).Unix(),
+	}
+
+	result, err := g.DbConn.Exec(
+		"INSERT INTO gang_wars (attacker_id, defender_id, attacker_tag, defender_tag, started_at, ends_at) VALUES (?, ?, ?, ?, ?, ?)",
+		attacker.ID,
+		defender.ID,
+		attacker.Tag,
+		defender.Tag,
+		war.StartedAt,
+		war.EndsAt,
+	)
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return nil, fmt.Errorf("you are already at war with [%s]", defender.Tag)
+	}
+
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return nil, errors.New("failed to declare war, system error")
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return nil, errors.New("failed to declare war, system error")
+	}
+
+	war.ID = uint64(id)
+
+	g.mu.Lock()
+	g.Wars[war.ID] = war
+	g.mu.Unlock()
+
+	return war, nil
+}
+
+// EndWar settles the war, pays out reputation to the winners and announces the result.
+// A nil winner means the war ended in a draw.
+func (g *Game) EndWar(war *GangWar, winner *Gang, result string) {
+	g.mu.Lock()
+	if _, ok := g.Wars[war.ID]; !ok {
+		g.mu.Unlock()
+		return
+	}
+	delete(g.Wars, war.ID)
+	g.mu.Unlock()
+
+	var winnerId uint64
+	if winner != nil {
+		winnerId = winner.ID
+	}
+
+	war.Mu.Lock()
+	_, err := g.DbConn.Exec(
+		"UPDATE gang_wars SET attacker_score = ?, defender_score = ?, winner_id = ?, result = ?, ended_at = ? WHERE id = ?",
+		war.AttackerScore,
+		war.DefenderScore,
+		winnerId,
+		result,
+		time.Now().Unix(),
+		war.ID,
+	)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+
+	score := fmt.Sprintf("[%s] %d - %d [%s]", war.Attacker.Tag, war.AttackerScore, war.DefenderScore, war.Defender.Tag)
+	winnerScore := 0
+	if winner != nil {
+		winnerScore = war.Score(winner)
+	}
+	war.Mu.Unlock()
+
+	// the news flash channel is unbuffered, so nothing is sent while holding the war lock
+
+	if winner == nil {
+		g.NewsFlash <- &responses.NewsFlash{
+			Msg: fmt.Sprintf("<NEWS FLASH> The war between [%s] and [%s] has ended in a stalemate, %s.", war.Attacker.Tag, war.Defender.Tag, score),
+		}
+		return
+	}
+
+	loser := war.Enemy(winner)
+	reward := int64(settings.GangWarWinRep + settings.GangWarRepPerKill*winnerScore)
+	winner.rewardReputation(reward)
+
+	var msg string
+	switch result {
+	case GangWarResultSurrender:
+		msg = fmt.Sprintf("<NEWS FLASH> [%s] has surrendered to [%s], %s.", loser.Tag, winner.Tag, score)
+	case GangWarResultDisband:
+		msg = fmt.Sprintf("<NEWS FLASH> [%s] has been disbanded, [%s] wins the war by default.", loser.Tag, winner.Tag)
+	default:
+		msg = fmt.Sprintf("<NEWS FLASH> The war is over, [%s] has beaten [%s], %s.", winner.Tag, loser.Tag, score)
+	}
+
+	g.NewsFlash <- &responses.NewsFlash{
+		Msg: msg,
+	}
+
+	winner.Notify(&responses.Generic{
+		Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+		Messages: []string{fmt.Sprintf("Your gang won the war against [%s], every member earned %d reputation.", loser.Tag, reward)},
+	})
+}
+
+// rewardReputation gives every member of the gang the reputation, online or not.
+func (g *Gang) rewardReputation(amount int64) {
+	online := []string{}
+
+	for _, p := range g.OnlineMembers() {
+		p.Mu.Lock()
+		p.Reputation += amount
+		p.Mu.Unlock()
+
+		online = append(online, fmt.Sprintf("%d", p.PlayerID))
+		go p.PlayerSendStatsUpdate()
+	}
+
+	query := "UPDATE characters SET reputation = reputation + ? WHERE gang_id = ?"
+	if len(online) > 0 {
+		query += " AND id NOT IN (" + strings.Join(online, ",") + ")"
+	}
+
+	_, err := g.Game.DbConn.Exec(query, amount, g.ID)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+}
+
+// EndAllWars forfeits every war the gang is in, used when the gang is disbanded.
+func (g *Gang) EndAllWars() {
+	for _, w := range g.ActiveWars() {
+		g.Game.EndWar(w, w.Enemy(g), GangWarResultDisband)
+	}
+}
+
+// CheckWars resolves wars which have run out of time, highest score wins.
+func (g *Game) CheckWars() {
+	now := time.Now().Unix()
+	expired := []*GangWar{}
+
+	g.mu.Lock()
+	for _, w := range g.Wars {
+		if w.EndsAt <= now {
+			expired = append(expired, w)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, w := range expired {
+		var winner *Gang
+
+		w.Mu.Lock()
+		switch {
+		case w.AttackerScore > w.DefenderScore:
+			winner = w.Attacker
+		case w.DefenderScore > w.AttackerScore:
+			winner = w.Defender
+		}
+		w.Mu.Unlock()
+
+		g.EndWar(w, winner, GangWarResultTimeout)
+	}
+}
+
+// LoadWars restores the wars which were still being fought when the server stopped.
+func (g *Game) LoadWars() {
+	rows, err := g.DbConn.Query("SELECT id, attacker_id, defender_id, attacker_score, defender_score, started_at, ends_at FROM gang_wars WHERE ended_at = 0")
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	wars := []models.GangWar{}
+	for rows.Next() {
+		w := models.GangWar{}
+		if err := rows.Scan(&w.Id, &w.AttackerId, &w.DefenderId, &w.AttackerScore, &w.DefenderScore, &w.StartedAt, &w.EndsAt); err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		wars = append(wars, w)
+	}
+	rows.Close()
+
+	for _, w := range wars {
+		attacker, err := g.GetGang(w.AttackerId)
+		if err != nil {
+			continue
+		}
+
+		defender, err := g.GetGang(w.DefenderId)
+		if err != nil {
+			continue
+		}
+
+		g.Wars[w.Id] = &GangWar{
+			ID:            w.Id,
+			Attacker:      attacker,
+			Defender:      defender,
+			AttackerScore: w.AttackerScore,
+			DefenderScore: w.DefenderScore,
+			StartedAt:     w.StartedAt,
+			EndsAt:        w.EndsAt,
+		}
+	}
+}
+
+// aimAtWarEnemies makes the player and any rivals at war at the same location take aim on each other.
+func (p *Entity) aimAtWarEnemies() {
+	loc := p.Loc
+	if p.Gang == nil || loc == nil || p.Dead || p.Hidden {
+		return
+	}
+
+	loc.mu.Lock()
+	enemies := []*Entity{}
+	for client := range loc.Players {
+		if client.Player != nil && client.Player != p {
+			enemies = append(enemies, client.Player)
+		}
+	}
+	loc.mu.Unlock()
+
+	for _, enemy := range enemies {
+		// either side may have moved, died or made peace since the list was taken
+		if p.Loc != loc || enemy.Loc != loc || p.Dead || enemy.Dead || enemy.Hidden || p.Gang == nil || p.Gang.WarWith(enemy.Gang) == nil {
+			continue
+		}
+
+		if p.CurrentTarget == nil {
+			action := CombatAction{
+				Target:   enemy,
+				Attacker: p,
+				Action:   CombatActionAim,
+			}
+			action.Execute()
+		}
+
+		if enemy.CurrentTarget == nil {
+			action := CombatAction{
+				Target:   p,
+				Attacker: enemy,
+				Action:   CombatActionAim,
+			}
+			action.Execute()
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package game
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/mreliasen/swi-server/internal/responses"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func TestDeclareWarMutual(t *testing.T) {
+	g := newLedgerTestGame(t)
+	g.Wars = make(map[uint64]*GangWar)
+	g.Players = make(map[*Entity]bool)
+	g.NewsFlash = make(chan protoreflect.ProtoMessage, 2)
+
+	a := &Gang{ID: 1, Name: "Testers", Tag: "TST", Game: g}
+	b := &Gang{ID: 2, Name: "Others", Tag: "OTH", Game: g}
+
+	// both gangs declare on each other at the same time
+	wg := sync.WaitGroup{}
+	errs := make([]error, 2)
+	for i, pair := range [][2]*Gang{{a, b}, {b, a}} {
+		wg.Add(1)
+		go func(i int, attacker *Gang, defender *Gang) {
+			defer wg.Done()
+			_, errs[i] = g.DeclareWar(attacker, defender)
+		}(i, pair[0], pair[1])
+	}
+	wg.Wait()
+
+	if (errs[0] == nil) == (errs[1] == nil) {
+		t.Fatalf("want exactly one declaration to succeed, got %v and %v", errs[0], errs[1])
+	}
+
+	if len(g.Wars) != 1 {
+		t.Errorf("%d active wars, want 1", len(g.Wars))
+	}
+
+	var rows int
+	if err := g.DbConn.QueryRow("SELECT COUNT(*) FROM gang_wars WHERE ended_at = 0").Scan(&rows); err != nil {
+		t.Fatal(err)
+	}
+
+	if rows != 1 {
+		t.Errorf("%d active wars saved, want 1", rows)
+	}
+
+	if msg := <-g.NewsFlash; msg.(*responses.NewsFlash).Msg == "" {
+		t.Errorf("declaration was not announced")
+	}
+
+	// the index holds even if a second active war bypasses the in-memory check
+	_, err := g.DbConn.Exec("INSERT INTO gang_wars (attacker_id, defender_id, attacker_tag, defender_tag) VALUES (2, 1, 'OTH', 'TST')")
+	if err == nil {
+		t.Errorf("second active war between the gangs was saved")
+	}
+}
@@ -1,37 +1,208 @@
 package game
 
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/database"
+	"github.com/mreliasen/swi-server/internal/database/models"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/responses"
+)
+
+type (
+	GangRole       uint8
+	GangPermission uint8
+)
+
+const (
+	GangRoleRecruit GangRole = 1
+	GangRoleMember  GangRole = 2
+	GangRoleOfficer GangRole = 3
+	GangRoleLeader  GangRole = 4
+)
+
+const (
+	GangPermInvite GangPermission = 1 << iota
+	GangPermKick
+	GangPermPromote
+	GangPermWithdraw
+	GangPermDeclareWar
+
+	GangPermAll = GangPermInvite | GangPermKick | GangPermPromote | GangPermWithdraw | GangPermDeclareWar
+)
+
+var GangRoleNames = map[GangRole]string{
+	GangRoleRecruit: "Recruit",
+	GangRoleMember:  "Member",
+	GangRoleOfficer: "Officer",
+	GangRoleLeader:  "Leader",
+}
+
+// ordered the way they are listed to players
+var GangPermissionNames = []struct {
+	Key  string
+	Perm GangPermission
+}{
+	{"invite", GangPermInvite},
+	{"kick", GangPermKick},
+	{"promote", GangPermPromote},
+	{"withdraw", GangPermWithdraw},
+	{"war", GangPermDeclareWar},
+}
+
+// permissions new gangs start out with, the leader can change these with /gang perms
+var DefaultGangPermissions = map[GangRole]GangPermission{
+	GangRoleRecruit: 0,
+	GangRoleMember:  GangPermWithdraw,
+	GangRoleOfficer: GangPermInvite | GangPermKick | GangPermPromote | GangPermWithdraw,
+	GangRoleLeader:  GangPermAll,
+}
+
 type Gang struct {
-	ID       uint64
-	Name     string
-	Tag      string
-	LeaderID uint64
-	Member   *Entity
+	ID          uint64
+	Name        string
+	Tag         string
+	LeaderID    uint64
+	Bank        int64
+	Stash       *Inventory
+	Permissions map[GangRole]GangPermission
+	Roles       map[uint64]GangRole
+	Game        *Game
+	Mu          sync.Mutex
+	ledger      []LedgerEntry
+	ledgerMu    sync.Mutex
+}
+
+type GangMember struct {
+	CharacterID uint64
+	Name        string
+	Role        GangRole
+}
+
+func GangRoleByName(name string) (GangRole, bool) {
+	for role, roleName := range GangRoleNames {
+		if strings.EqualFold(roleName, name) {
+			return role, true
+		}
+	}
+
+	return 0, false
+}
+
+func GangPermissionByName(name string) (GangPermission, bool) {
+	for _, p := range GangPermissionNames {
+		if strings.EqualFold(p.Key, name) {
+			return p.Perm, true
+		}
+	}
+
+	return 0, false
 }
 
 func (g *Gang) IsLeader(e *Entity) bool {
+	g.Mu.Lock()
+	defer g.Mu.Unlock()
+
 	return g.LeaderID == e.PlayerID
 }
 
+func (g *Gang) Role(characterId uint64) GangRole {
+	g.Mu.Lock()
+	defer g.Mu.Unlock()
+
+	return g.role(characterId)
+}
+
+// role is Role for callers which already hold g.Mu. Characters without a gang_members
+// row get the lowest role, so a missing row never grants more than it should.
+func (g *Gang) role(characterId uint64) GangRole {
+	if characterId == g.LeaderID {
+		return GangRoleLeader
+	}
+
+	if role, ok := g.Roles[characterId]; ok {
+		return role
+	}
+
+	logger.Logger.Warn(fmt.Sprintf("Character %d has no role in gang [%s], treating them as a recruit.", characterId, g.Tag))
+	return GangRoleRecruit
+}
+
+// Can checks if the member's role has been granted the permission. Leaders can do everything.
+func (g *Gang) Can(e *Entity, perm GangPermission) bool {
+	g.Mu.Lock()
+	defer g.Mu.Unlock()
+
+	return g.can(e, perm)
+}
+
+// can is Can for callers which already hold g.Mu.
+func (g *Gang) can(e *Entity, perm GangPermission) bool {
+	if e.Gang != g {
+		return false
+	}
+
+	role := g.role(e.PlayerID)
+	if role == GangRoleLeader {
+		return true
+	}
+
+	return g.Permissions[role]&perm == perm
+}
+
+func (g *Gang) SetRole(characterId uint64, role GangRole) bool {
+	_, err := g.Game.DbConn.Exec(
+		`INSERT INTO gang_members (character_id, gang_id, role, joined_at, last_chat_id)
+        VALUES (?, ?, ?, ?, (SELECT COALESCE(MAX(id), 0) FROM gang_chat WHERE gang_id = ?))
+        ON CONFLICT(character_id) DO UPDATE SET role = excluded.role`,
+		characterId,
+		g.ID,
+		role,
+		time.Now().Unix(),
+		g.ID,
+	)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return false
+	}
+
+	g.Mu.Lock()
+	g.Roles[characterId] = role
+	g.Mu.Unlock()
+
+	return true
+}
+
 func (g *Gang) Save() bool {
+	permissions, err := json.Marshal(g.Permissions)
+	if err != nil {
+		print(err.Error())
+		return false
+	}
+
 	if g.ID <= 0 {
-		_, err := g.Member.Client.Game.DbConn.Exec(
-			"INSERT INTO gangs (name, tag, leader_id ) VALUES (?, ?, ?)",
-			g.Name,
-			g.Tag,
-			g.LeaderID,
-		)
-		if err != nil {
+		if err := g.insert(string(permissions)); err != nil {
 			print(err.Error())
+			return false
 		}
 
-		return err == nil
+		return true
 	}
 
-	_, err := g.Member.Client.Game.DbConn.Exec(
-		"UPDATE gangs SET name = ?, tag = ?, leader_id = ? WHERE id = ?",
+	_, err = g.Game.DbConn.Exec(
+		"UPDATE gangs SET name = ?, tag = ?, leader_id = ?, permissions = ?, bank = ? WHERE id = ?",
 		g.Name,
 		g.Tag,
 		g.LeaderID,
+		string(permissions),
+		g.Bank,
 		g.ID,
 	)
 	if err != nil {
@@ -40,3 +211,383 @@ func (g *Gang) Save() bool {
 
 	return err == nil
 }
+
+func (g *Gang) insert(permissions string) error {
+	result, err := g.Game.DbConn.Exec(
+		"INSERT INTO gangs (name, tag, leader_id, permissions, bank) VALUES (?, ?, ?, ?, ?)",
+		g.Name,
+		g.Tag,
+		g.LeaderID,
+		permissions,
+		g.Bank,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	g.ID = uint64(id)
+	return nil
+}
+
+// OnlineMembers returns all logged in players who belong to the gang.
+func (g *Gang) OnlineMembers() []*Entity {
+	members := []*Entity{}
+
+	for _, p := range g.Game.OnlinePlayers() {
+		if p.Gang == g {
+			members = append(members, p)
+		}
+	}
+
+	return members
+}
+
+// Members returns every character in the gang, online or not.
+func (g *Gang) Members() []GangMember {
+	members := []GangMember{}
+
+	rows, err := g.Game.DbConn.Query("SELECT id, name FROM characters WHERE gang_id = ?", g.ID)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return members
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		character := models.Character{}
+		if err := rows.Scan(&character.Id, &character.Name); err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		members = append(members, GangMember{
+			CharacterID: character.Id,
+			Name:        character.Name,
+			Role:        g.Role(character.Id),
+		})
+	}
+
+	return members
+}
+
+func (g *Gang) GetMember(name string) (GangMember, bool) {
+	for _, m := range g.Members() {
+		if strings.EqualFold(m.Name, name) {
+			return m, true
+		}
+	}
+
+	return GangMember{}, false
+}
+
+// RemoveMember takes the character out of the gang, whether they are online or not.
+func (g *Gang) RemoveMember(member GangMember) bool {
+	if p := g.Game.GetPlayerClient(member.CharacterID); p != nil {
+		return p.SetGang(nil, 0)
+	}
+
+	_, err := g.Game.DbConn.Exec("UPDATE characters SET gang_id = 0 WHERE id = ? AND gang_id = ?", member.CharacterID, g.ID)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return false
+	}
+
+	_, err = g.Game.DbConn.Exec("DELETE FROM gang_members WHERE character_id = ?", member.CharacterID)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+
+	g.Mu.Lock()
+	delete(g.Roles, member.CharacterID)
+	g.Mu.Unlock()
+
+	return err == nil
+}
+
+// Notify sends the event to all online gang members.
+func (g *Gang) Notify(msg *responses.Generic) {
+	for _, p := range g.OnlineMembers() {
+		p.Client.SendEvent(msg)
+	}
+}
+
+// Chat sends the message to all online members and stores it, so members
+// who are offline get it the next time they log in.
+func (g *Gang) Chat(sender *Entity, message string) {
+	result, err := g.Game.DbConn.Exec(
+		"INSERT INTO gang_chat (gang_id, character_id, name, message, created_at) VALUES (?, ?, ?, ?, ?)",
+		g.ID,
+		sender.PlayerID,
+		sender.Name,
+		message,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+
+	event := &responses.Chat{
+		Type:   responses.ChatType_CHAT_TYPE_GANG,
+		Player: sender.PlayerGameFrame(),
+		Msg:    message,
+	}
+
+	members := []uint64{}
+	for _, p := range g.OnlineMembers() {
+		p.Client.SendEvent(event)
+		members = append(members, p.PlayerID)
+	}
+
+	if err != nil {
+		return
+	}
+
+	if id, err := result.LastInsertId(); err == nil {
+		g.MarkChatRead(uint64(id), members...)
+	}
+}
+
+// SendChatBacklog sends the player the gang chat messages posted since they were last online.
+func (g *Gang) SendChatBacklog(p *Entity) {
+	rows, err := g.Game.DbConn.Query(
+		`SELECT
+            id,
+            character_id,
+            name,
+            message
+        FROM
+            gang_chat
+        WHERE
+            gang_id = ?
+            AND id > COALESCE((SELECT last_chat_id FROM gang_members WHERE character_id = ?), 0)
+        ORDER BY
+            id DESC
+        LIMIT
+            ?`,
+		g.ID,
+		p.PlayerID,
+		settings.GangChatBacklog,
+	)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	backlog := []models.GangChat{}
+	for rows.Next() {
+		msg := models.GangChat{}
+		if err := rows.Scan(&msg.Id, &msg.CharacterId, &msg.Name, &msg.Message); err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		backlog = append(backlog, msg)
+	}
+	rows.Close()
+
+	if len(backlog) == 0 {
+		return
+	}
+
+	p.Client.SendEvent(&responses.Generic{
+		Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+		Messages: []string{fmt.Sprintf("You have %d unread gang message(s):", len(backlog))},
+	})
+
+	for i := len(backlog) - 1; i >= 0; i-- {
+		p.Client.SendEvent(&responses.Chat{
+			Type: responses.ChatType_CHAT_TYPE_GANG,
+			Player: &responses.Player{
+				Id:      backlog[i].CharacterId,
+				Name:    backlog[i].Name,
+				GangTag: g.Tag,
+			},
+			Msg: backlog[i].Message,
+		})
+	}
+
+	// the backlog is newest first
+	g.MarkChatRead(backlog[0].Id, p.PlayerID)
+}
+
+// MarkChatRead flags the gang chat messages up to chatId as seen by the characters,
+// as soon as they are delivered so a restart does not send them again.
+func (g *Gang) MarkChatRead(chatId uint64, characterIds ...uint64) {
+	if len(characterIds) == 0 {
+		return
+	}
+
+	args := []any{chatId, g.ID}
+	for _, id := range characterIds {
+		args = append(args, id)
+	}
+
+	_, err := g.Game.DbConn.Exec(
+		"UPDATE gang_members SET last_chat_id = MAX(last_chat_id, ?) WHERE gang_id = ? AND character_id IN (?"+strings.Repeat(", ?", len(characterIds)-1)+")",
+		args...,
+	)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+}
+
+// PruneGangChat removes gang chat messages older than settings.GangChatRetainDays.
+func (g *Game) PruneGangChat() {
+	cutoff := time.Now().AddDate(0, 0, -settings.GangChatRetainDays).Unix()
+
+	_, err := g.DbConn.Exec("DELETE FROM gang_chat WHERE created_at < ?", cutoff)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+}
+
+// Disband deletes the gang and everything it owns. The gang lock is only held to clear its
+// in-memory state; the database, turf and members are updated after it is released.
+func (g *Gang) Disband() bool {
+	err := database.WithTx(g.Game.DbConn, func(tx *sql.Tx) error {
+		if _, err := tx.Exec("UPDATE characters SET gang_id = 0 WHERE gang_id = ?", g.ID); err != nil {
+			return err
+		}
+
+		for _, table := range []string{"gang_members", "gang_chat", "gang_stash", "gang_bank_log"} {
+			if _, err := tx.Exec("DELETE FROM "+table+" WHERE gang_id = ?", g.ID); err != nil {
+				return err
+			}
+		}
+
+		_, err := tx.Exec("DELETE FROM gangs WHERE id = ?", g.ID)
+		return err
+	})
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return false
+	}
+
+	g.Game.mu.Lock()
+	delete(g.Game.Gangs, g.ID)
+	g.Game.mu.Unlock()
+
+	// anyone still holding on to the gang is left with the lowest role
+	g.Mu.Lock()
+	g.Roles = make(map[uint64]GangRole)
+	g.Mu.Unlock()
+
+	g.releaseAllTurf()
+
+	for _, p := range g.OnlineMembers() {
+		p.Mu.Lock()
+		p.Gang = nil
+		p.Mu.Unlock()
+		p.PlayerBroadcastGangTag()
+	}
+
+	// the result is announced on the news flash channel
+	g.EndAllWars()
+
+	return true
+}
+
+// CreateGang saves the new gang. Names and tags are unique regardless of case, which the
+// database enforces, so two players cannot both claim the same one.
+func (g *Game) CreateGang(leader *Entity, name string, tag string) (*Gang, error) {
+	permissions := map[GangRole]GangPermission{}
+	for role, perm := range DefaultGangPermissions {
+		permissions[role] = perm
+	}
+
+	gang := &Gang{
+		Name:        name,
+		Tag:         tag,
+		LeaderID:    leader.PlayerID,
+		Stash:       NewInventory(nil),
+		Permissions: permissions,
+		Roles:       make(map[uint64]GangRole),
+		Game:        g,
+	}
+
+	encoded, err := json.Marshal(permissions)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return nil, errors.New("failed to create gang")
+	}
+
+	err = gang.insert(string(encoded))
+	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		return nil, errors.New("that gang name or tag is already taken")
+	}
+
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return nil, errors.New("failed to create gang")
+	}
+
+	g.mu.Lock()
+	g.Gangs[gang.ID] = gang
+	g.mu.Unlock()
+
+	return gang, nil
+}
+
+// SetGang updates the players gang and role, keeps characters.gang_id in sync
+// and lets everyone know about the new tag.
+func (p *Entity) SetGang(gang *Gang, role GangRole) bool {
+	var gangId uint64
+	if gang != nil {
+		gangId = gang.ID
+	}
+
+	_, err := p.Client.Game.DbConn.Exec("UPDATE characters SET gang_id = ? WHERE id = ?", gangId, p.PlayerID)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return false
+	}
+
+	if gang != nil {
+		if !gang.SetRole(p.PlayerID, role) {
+			return false
+		}
+	} else if p.Gang != nil {
+		_, err := p.Client.Game.DbConn.Exec("DELETE FROM gang_members WHERE character_id = ?", p.PlayerID)
+		if err != nil {
+			logger.Logger.Error(err.Error())
+		}
+
+		p.Gang.Mu.Lock()
+		delete(p.Gang.Roles, p.PlayerID)
+		p.Gang.Mu.Unlock()
+	}
+
+	p.Mu.Lock()
+	p.Gang = gang
+	p.GangInvite = nil
+	p.Mu.Unlock()
+
+	p.PlayerBroadcastGangTag()
+	return true
+}
+
+func (p *Entity) PlayerBroadcastGangTag() {
+	if p.Client == nil {
+		return
+	}
+
+	p.Client.Game.GlobalEvents <- &responses.PlayerList{
+		Type:     responses.PlayerEvent_EVENT_TYPE_PLAYER_JOIN,
+		Id:       p.Client.UUID,
+		Name:     p.Name,
+		Hometown: p.Hometown,
+		GangTag:  p.GangTag(),
+	}
+
+	if p.Loc != nil {
+		for client := range p.Loc.Players {
+			go client.Player.sendGameFrame(false)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package game
+
+import "testing"
+
+func TestCreateGangUnique(t *testing.T) {
+	g := newLedgerTestGame(t)
+	g.Gangs = make(map[uint64]*Gang)
+	leader := &Entity{PlayerID: 1}
+
+	tests := []struct {
+		name    string
+		gang    string
+		tag     string
+		wantErr bool
+	}{
+		{name: "name taken in another case", gang: "testers", tag: "NEW", wantErr: true},
+		{name: "tag taken in another case", gang: "New Gang", tag: "tst", wantErr: true},
+		{name: "free name and tag", gang: "New Gang", tag: "NEW"},
+		{name: "just created", gang: "NEW GANG", tag: "NEW2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gang, err := g.CreateGang(leader, tt.gang, tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+
+			if err == nil && !g.HasGang(gang.ID) {
+				t.Errorf("created gang was not loaded")
+			}
+		})
+	}
+}
+
+func TestGangRoleFailsClosed(t *testing.T) {
+	quietLogger()
+
+	gang := &Gang{
+		LeaderID:    1,
+		Permissions: DefaultGangPermissions,
+		Roles:       map[uint64]GangRole{2: GangRoleOfficer},
+	}
+
+	tests := []struct {
+		name        string
+		characterId uint64
+		want        GangRole
+	}{
+		{name: "leader", characterId: 1, want: GangRoleLeader},
+		{name: "saved role", characterId: 2, want: GangRoleOfficer},
+		{name: "no role saved", characterId: 3, want: GangRoleRecruit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := gang.Role(tt.characterId); got != tt.want {
+				t.Errorf("got role %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	if gang.Can(&Entity{PlayerID: 3, Gang: gang}, GangPermWithdraw) {
+		t.Errorf("character without a saved role can withdraw from the bank")
+	}
+}
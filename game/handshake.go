@@ -0,0 +1,120 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/responses"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+type Feature string
+
+const (
+	FeatureGPS         Feature = "gps"
+	FeatureCompression Feature = "compression"
+)
+
+// ServerFeatures are the capabilities the server can turn on for a client.
+var ServerFeatures = []Feature{FeatureGPS, FeatureCompression}
+
+// clients which never send a handshake get what every client had before handshakes
+func defaultFeatures() map[Feature]bool {
+	return map[Feature]bool{
+		FeatureGPS: true,
+	}
+}
+
+func (c *Client) HasFeature(feature Feature) bool {
+	c.featuresMu.Lock()
+	defer c.featuresMu.Unlock()
+
+	return c.features[feature]
+}
+
+func (c *Client) Handshaken() bool {
+	c.featuresMu.Lock()
+	defer c.featuresMu.Unlock()
+
+	return c.handshaken
+}
+
+// handshakeIndex finds the reply to the client's handshake in a batch of messages, or returns -1.
+func handshakeIndex(msgs []protoreflect.ProtoMessage) int {
+	for i, msg := range msgs {
+		if system, ok := msg.(*responses.System); ok && system.Type == responses.SystemType_HANDSHAKE {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (c *Client) sendHandshake(handshake *responses.Handshake) {
+	handshake.ProtocolVersion = settings.ProtocolVersion
+	handshake.MinClientVersion = settings.ClientVersionCheck
+
+	payload, err := anypb.New(handshake)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	c.SendEvent(&responses.System{
+		Type:     responses.SystemType_HANDSHAKE,
+		AnyField: payload,
+	})
+}
+
+// Handshake checks the client version and turns on the requested capabilities the server supports.
+// Clients older than ClientVersionCheck are disconnected.
+func (c *Client) Handshake(version int64, capabilities []string) {
+	if version < settings.ClientVersionCheck {
+		message := "Your client is out of date, please update it to keep playing."
+
+		c.sendHandshake(&responses.Handshake{
+			Accepted: false,
+			Message:  message,
+		})
+		c.SendEvent(&responses.Generic{
+			Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+			Messages: []string{message},
+		})
+
+		logger.Logger.Info(fmt.Sprintf("Rejected client %s with version %d", c.UUID, version))
+		c.Send.Close()
+		return
+	}
+
+	requested := map[string]bool{}
+	for _, capability := range capabilities {
+		requested[strings.ToLower(capability)] = true
+	}
+
+	features := map[Feature]bool{}
+	enabled := []string{}
+	for _, feature := range ServerFeatures {
+		// compression can only be used when permessage-deflate was negotiated on connect
+		if feature == FeatureCompression && !c.Game.Compression {
+			continue
+		}
+
+		if requested[string(feature)] {
+			features[feature] = true
+			enabled = append(enabled, string(feature))
+		}
+	}
+
+	c.featuresMu.Lock()
+	c.features = features
+	c.handshaken = true
+	c.featuresMu.Unlock()
+
+	c.sendHandshake(&responses.Handshake{
+		Accepted:     true,
+		Capabilities: enabled,
+	})
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/mreliasen/swi-server/game/settings"
 	"github.com/mreliasen/swi-server/internal"
@@ -133,7 +132,7 @@ func (inv *Inventory) addItem(x *Item) error {
 			x.Loc = nil
 			inv.Items[i] = x
 
-			if inv.Owner.IsPlayer && x.TemplateName == "smartphone" {
+			if inv.Owner != nil && inv.Owner.IsPlayer && x.TemplateName == "smartphone" {
 				go inv.Owner.PlayerSendMapUpdate()
 			}
 
@@ -261,14 +260,57 @@ func (inv *Inventory) drop(i int) *ItemMoved {
 	}
 }
 
-func (i *Inventory) save() bool {
-	if i.Owner == nil {
-		return false
+// slotOf returns the slot the item is in, or -1 if it is not in the inventory.
+func (inv *Inventory) slotOf(item *Item) int {
+	inv.Mu.Lock()
+	defer inv.Mu.Unlock()
+
+	for i, slot := range inv.Items {
+		if slot == item {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// remove takes the item out of the given slot without placing it anywhere.
+func (inv *Inventory) remove(i int) *Item {
+	inv.Mu.Lock()
+	defer inv.Mu.Unlock()
+
+	if i < 0 || i >= len(inv.Items) || inv.Items[i] == nil {
+		return nil
 	}
 
+	item := inv.Items[i]
+	inv.Items[i] = nil
+	item.Inventory = nil
+
+	if inv.Equipment[item.GetItemType()] == item {
+		inv.Equipment[item.GetItemType()] = nil
+	}
+
+	return item
+}
+
+func (i *Inventory) load() {
 	i.Mu.Lock()
 	defer i.Mu.Unlock()
 
+	if !i.Owner.IsPlayer {
+		return
+	}
+
+	row := i.Owner.Client.Game.DbConn.QueryRow("SELECT inventory FROM inventory WHERE user_id = ? LIMIT 1", i.Owner.Client.UserId)
+	invData := models.Inventory{}
+	row.Scan(&invData.Inventory)
+
+	i.deserialize(invData.Inventory)
+}
+
+// serialize encodes the items as ItemSaveContainers, the caller must hold the lock.
+func (i *Inventory) serialize() (string, error) {
 	payload := []ItemSaveContainer{}
 
 	for _, item := range i.Items {
@@ -288,42 +330,23 @@ func (i *Inventory) save() bool {
 
 	val, err := json.Marshal(payload)
 	if err != nil {
-		print(err.Error())
-		return false
-	}
-
-	_, err = i.Owner.Client.Game.DbConn.Exec(
-		"INSERT OR REPLACE INTO inventory (user_id, inventory, updated_at) VALUES(?, ?, ?)",
-		i.Owner.Client.UserId, string(val), time.Now().Unix(),
-	)
-	if err != nil {
-		print(err.Error())
+		return "", err
 	}
 
-	return err == nil
+	return string(val), nil
 }
 
-func (i *Inventory) load() {
-	i.Mu.Lock()
-	defer i.Mu.Unlock()
-
-	if !i.Owner.IsPlayer {
-		return
-	}
-
-	row := i.Owner.Client.Game.DbConn.QueryRow("SELECT inventory FROM inventory WHERE user_id = ? LIMIT 1", i.Owner.Client.UserId)
-	invData := models.Inventory{}
-	row.Scan(&invData.Inventory)
-
-	if invData.Inventory == "" {
+// deserialize restores items saved with serialize, the caller must hold the lock.
+func (i *Inventory) deserialize(data string) {
+	if data == "" {
 		return
 	}
 
 	items := []ItemSaveContainer{}
-	json.Unmarshal([]byte(invData.Inventory), &items)
+	json.Unmarshal([]byte(data), &items)
 
 	for index, item := range items {
-		if item.ID == "" {
+		if item.ID == "" || index >= len(i.Items) {
 			continue
 		}
 
@@ -41,6 +41,7 @@ type Item struct {
 	AmmoWear     float32
 	Inventory    *Inventory
 	Loc          *Location
+	DroppedAt    int64 // when the item was put on the ground, for decay
 	UseEffect    *ItemUseEffect
 }
 
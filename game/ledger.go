@@ -0,0 +1,336 @@
+package game
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mreliasen/swi-server/internal/database"
+	"github.com/mreliasen/swi-server/internal/logger"
+)
+
+// LedgerReason is the reason code booked with every money movement.
+type LedgerReason string
+
+const (
+	LedgerOpening      LedgerReason = "opening"
+	LedgerSell         LedgerReason = "sell"
+	LedgerBuy          LedgerReason = "buy"
+	LedgerHeal         LedgerReason = "heal"
+	LedgerDrink        LedgerReason = "drink"
+	LedgerTravel       LedgerReason = "travel"
+	LedgerDeathLoot    LedgerReason = "death-loot"
+	LedgerRespawn      LedgerReason = "respawn"
+	LedgerKillReward   LedgerReason = "kill-reward"
+	LedgerTransfer     LedgerReason = "transfer"
+	LedgerPhoneFee     LedgerReason = "phone-fee"
+	LedgerDeposit      LedgerReason = "deposit"
+	LedgerWithdraw     LedgerReason = "withdraw"
+	LedgerGangCreate   LedgerReason = "gang-create"
+	LedgerGangDeposit  LedgerReason = "gang-deposit"
+	LedgerGangWithdraw LedgerReason = "gang-withdraw"
+	LedgerTurfCut      LedgerReason = "turf-cut"
+	LedgerReversal     LedgerReason = "reversal"
+)
+
+const (
+	LedgerAccountCash  = "cash"
+	LedgerAccountBank  = "bank"
+	LedgerAccountGang  = "gang"
+	LedgerAccountWorld = "world"
+)
+
+// LedgerAccount is one side of a money movement, with its balance after the movement.
+type LedgerAccount struct {
+	Type    string
+	ID      uint64
+	Balance int64
+	player  *Entity
+	gang    *Gang
+}
+
+// WorldAccount is where money comes from and goes to when it enters or leaves the economy.
+var WorldAccount = LedgerAccount{Type: LedgerAccountWorld}
+
+type LedgerEntry struct {
+	TxnID     string
+	Account   LedgerAccount
+	Amount    int64
+	Reason    LedgerReason
+	Ref       string
+	CreatedAt int64
+}
+
+// CashAccount is the cash the entity carries, NPC money belongs to the world.
+func (e *Entity) CashAccount() LedgerAccount {
+	if !e.IsPlayer {
+		return WorldAccount
+	}
+
+	return LedgerAccount{Type: LedgerAccountCash, ID: e.PlayerID, Balance: e.Cash, player: e}
+}
+
+func (e *Entity) BankAccount() LedgerAccount {
+	return LedgerAccount{Type: LedgerAccountBank, ID: e.PlayerID, Balance: e.Bank, player: e}
+}
+
+func (g *Gang) BankAccount() LedgerAccount {
+	return LedgerAccount{Type: LedgerAccountGang, ID: g.ID, Balance: g.Bank, gang: g}
+}
+
+// RecordLedger books the amount moving from one account to the other, once the balances
+// have been updated. Each side is kept with the player or gang owning the account until it
+// is written along with their balance, the world side is kept with the other side.
+func RecordLedger(reason LedgerReason, amount int64, from LedgerAccount, to LedgerAccount, ref string) {
+	if amount == 0 {
+		return
+	}
+
+	txnId := uuid.NewString()
+	now := time.Now().Unix()
+
+	debit := LedgerEntry{TxnID: txnId, Account: from, Amount: -amount, Reason: reason, Ref: ref, CreatedAt: now}
+	credit := LedgerEntry{TxnID: txnId, Account: to, Amount: amount, Reason: reason, Ref: ref, CreatedAt: now}
+
+	if !from.book(debit) && !to.book(debit) {
+		logger.Logger.Warn("ledger entry without an owner: " + string(reason))
+	}
+
+	if !to.book(credit) && !from.book(credit) {
+		logger.Logger.Warn("ledger entry without an owner: " + string(reason))
+	}
+}
+
+// book keeps the entry with the owner of the account, if it has one.
+func (a LedgerAccount) book(entry LedgerEntry) bool {
+	switch {
+	case a.player != nil:
+		a.player.ledgerMu.Lock()
+		a.player.ledger = append(a.player.ledger, entry)
+		a.player.ledgerMu.Unlock()
+		return true
+
+	case a.gang != nil:
+		a.gang.ledgerMu.Lock()
+		a.gang.ledger = append(a.gang.ledger, entry)
+		a.gang.ledgerMu.Unlock()
+		return true
+	}
+
+	return false
+}
+
+// pendingLedger returns the entries not yet written, call ledgerWritten once they are.
+func (e *Entity) pendingLedger() []LedgerEntry {
+	e.ledgerMu.Lock()
+	defer e.ledgerMu.Unlock()
+
+	return append([]LedgerEntry{}, e.ledger...)
+}
+
+func (e *Entity) ledgerWritten(count int) {
+	e.ledgerMu.Lock()
+	e.ledger = e.ledger[count:]
+	e.ledgerMu.Unlock()
+}
+
+func (g *Gang) pendingLedger() []LedgerEntry {
+	g.ledgerMu.Lock()
+	defer g.ledgerMu.Unlock()
+
+	return append([]LedgerEntry{}, g.ledger...)
+}
+
+func (g *Gang) ledgerWritten(count int) {
+	g.ledgerMu.Lock()
+	g.ledger = g.ledger[count:]
+	g.ledgerMu.Unlock()
+}
+
+func writeLedger(tx *sql.Tx, entries []LedgerEntry) error {
+	for _, entry := range entries {
+		_, err := tx.Exec(
+			"INSERT INTO ledger (txn_id, account_type, account_id, amount, balance, reason, ref, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			entry.TxnID,
+			entry.Account.Type,
+			entry.Account.ID,
+			entry.Amount,
+			entry.Account.Balance,
+			string(entry.Reason),
+			entry.Ref,
+			entry.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveBalances writes the player's cash and bank along with the ledger entries behind them,
+// the caller must hold the lock and call ledgerWritten once the transaction commits.
+func (e *Entity) saveBalances(tx *sql.Tx, entries []LedgerEntry) error {
+	_, err := tx.Exec("UPDATE characters SET cash = ?, bank = ? WHERE id = ?", e.Cash, e.Bank, e.PlayerID)
+	if err != nil {
+		return err
+	}
+
+	return writeLedger(tx, entries)
+}
+
+// saveBank writes the gang bank along with the ledger entries behind it,
+// call ledgerWritten once the transaction commits.
+func (g *Gang) saveBank(tx *sql.Tx, entries []LedgerEntry) error {
+	_, err := tx.Exec("UPDATE gangs SET bank = ? WHERE id = ?", g.Bank, g.ID)
+	if err != nil {
+		return err
+	}
+
+	return writeLedger(tx, entries)
+}
+
+// openLedger books the starting balances of characters who have no ledger history yet.
+func (g *Game) openLedger(p *Entity) {
+	var count int
+	row := g.DbConn.QueryRow(
+		"SELECT COUNT(*) FROM ledger WHERE account_type IN (?, ?) AND account_id = ?",
+		LedgerAccountCash,
+		LedgerAccountBank,
+		p.PlayerID,
+	)
+	if err := row.Scan(&count); err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	if count > 0 {
+		return
+	}
+
+	RecordLedger(LedgerOpening, p.Cash, WorldAccount, p.CashAccount(), "")
+	RecordLedger(LedgerOpening, p.Bank, WorldAccount, p.BankAccount(), "")
+
+	entries := p.pendingLedger()
+	err := database.WithTx(g.DbConn, func(tx *sql.Tx) error {
+		return writeLedger(tx, entries)
+	})
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	p.ledgerWritten(len(entries))
+}
+
+// openGangLedger books the bank balance of gangs which have no ledger history yet.
+func (g *Game) openGangLedger(gang *Gang) {
+	var count int
+	row := g.DbConn.QueryRow("SELECT COUNT(*) FROM ledger WHERE account_type = ? AND account_id = ?", LedgerAccountGang, gang.ID)
+	if err := row.Scan(&count); err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	if count > 0 {
+		return
+	}
+
+	RecordLedger(LedgerOpening, gang.Bank, WorldAccount, gang.BankAccount(), "")
+
+	entries := gang.pendingLedger()
+	err := database.WithTx(g.DbConn, func(tx *sql.Tx) error {
+		return writeLedger(tx, entries)
+	})
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	gang.ledgerWritten(len(entries))
+}
+
+type LedgerMismatch struct {
+	Name    string
+	Account string
+	Balance int64
+	Ledger  int64
+}
+
+// Reconcile compares the saved balances of every character and gang with the sum of their
+// ledger entries, and counts the transactions which do not balance out. Money moving between
+// two players is written with each of their saves, so it can be off until both have saved.
+func (g *Game) Reconcile() ([]LedgerMismatch, int, error) {
+	mismatches := []LedgerMismatch{}
+
+	rows, err := g.DbConn.Query(`
+        SELECT
+            c.name,
+            c.cash,
+            c.bank,
+            COALESCE((SELECT SUM(amount) FROM ledger WHERE account_type = ? AND account_id = c.id), 0),
+            COALESCE((SELECT SUM(amount) FROM ledger WHERE account_type = ? AND account_id = c.id), 0)
+        FROM
+            characters c`,
+		LedgerAccountCash,
+		LedgerAccountBank,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for rows.Next() {
+		var name string
+		var cash, bank, cashLedger, bankLedger int64
+
+		if err := rows.Scan(&name, &cash, &bank, &cashLedger, &bankLedger); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+
+		if cash != cashLedger {
+			mismatches = append(mismatches, LedgerMismatch{name, LedgerAccountCash, cash, cashLedger})
+		}
+
+		if bank != bankLedger {
+			mismatches = append(mismatches, LedgerMismatch{name, LedgerAccountBank, bank, bankLedger})
+		}
+	}
+	rows.Close()
+
+	rows, err = g.DbConn.Query(`
+        SELECT
+            g.tag,
+            g.bank,
+            COALESCE((SELECT SUM(amount) FROM ledger WHERE account_type = ? AND account_id = g.id), 0)
+        FROM
+            gangs g`,
+		LedgerAccountGang,
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for rows.Next() {
+		var tag string
+		var bank, bankLedger int64
+
+		if err := rows.Scan(&tag, &bank, &bankLedger); err != nil {
+			rows.Close()
+			return nil, 0, err
+		}
+
+		if bank != bankLedger {
+			mismatches = append(mismatches, LedgerMismatch{"[" + tag + "]", LedgerAccountGang, bank, bankLedger})
+		}
+	}
+	rows.Close()
+
+	var unbalanced int
+	row := g.DbConn.QueryRow("SELECT COUNT(*) FROM (SELECT txn_id FROM ledger GROUP BY txn_id HAVING SUM(amount) != 0)")
+	if err := row.Scan(&unbalanced); err != nil {
+		return nil, 0, err
+	}
+
+	return mismatches, unbalanced, nil
+}
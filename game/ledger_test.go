@@ -0,0 +1,140 @@
+package game
+
+import (
+	"database/sql"
+	"io"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/mreliasen/swi-server/internal/database"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/pterm/pterm"
+)
+
+var quietLoggerOnce sync.Once
+
+// quietLogger discards log output. It is only set once, as goroutines started by earlier
+// tests may still be logging.
+func quietLogger() {
+	quietLoggerOnce.Do(func() {
+		logger.Logger = pterm.DefaultLogger.WithWriter(io.Discard)
+	})
+}
+
+// newLedgerTestGame gives the game a fresh, migrated database with a character and a gang.
+func newLedgerTestGame(t *testing.T) *Game {
+	quietLogger()
+
+	db, err := sql.Open("libsql", "file:"+filepath.Join(t.TempDir(), "ledger.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err := database.Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("INSERT INTO characters (id, user_id, name, cash, bank) VALUES (1, 1, 'Tester', 100, 50)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = db.Exec("INSERT INTO gangs (id, name, tag, leader_id, bank) VALUES (1, 'Testers', 'TST', 1, 200)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &Game{DbConn: db}
+}
+
+func entry(txn string, account string, amount int64) LedgerEntry {
+	id := uint64(1)
+	if account == LedgerAccountWorld {
+		id = 0
+	}
+
+	return LedgerEntry{TxnID: txn, Account: LedgerAccount{Type: account, ID: id}, Amount: amount, Reason: LedgerTransfer}
+}
+
+func TestReconcile(t *testing.T) {
+	opening := []LedgerEntry{
+		entry("open-cash", LedgerAccountWorld, -100),
+		entry("open-cash", LedgerAccountCash, 100),
+		entry("open-bank", LedgerAccountWorld, -50),
+		entry("open-bank", LedgerAccountBank, 50),
+		entry("open-gang", LedgerAccountWorld, -200),
+		entry("open-gang", LedgerAccountGang, 200),
+	}
+
+	tests := []struct {
+		name       string
+		entries    []LedgerEntry
+		mismatches []LedgerMismatch
+		unbalanced int
+	}{
+		{
+			name:       "balances match the ledger",
+			entries:    opening,
+			mismatches: []LedgerMismatch{},
+		},
+		{
+			name:    "no ledger history",
+			entries: nil,
+			mismatches: []LedgerMismatch{
+				{"Tester", LedgerAccountCash, 100, 0},
+				{"Tester", LedgerAccountBank, 50, 0},
+				{"[TST]", LedgerAccountGang, 200, 0},
+			},
+		},
+		{
+			name: "deposit missing from the saved balances",
+			entries: append(append([]LedgerEntry{}, opening...),
+				entry("deposit", LedgerAccountCash, -30),
+				entry("deposit", LedgerAccountBank, 30),
+			),
+			mismatches: []LedgerMismatch{
+				{"Tester", LedgerAccountCash, 100, 70},
+				{"Tester", LedgerAccountBank, 50, 80},
+			},
+		},
+		{
+			name: "only one side of a transaction written",
+			entries: append(append([]LedgerEntry{}, opening...),
+				entry("gang-deposit", LedgerAccountGang, 25),
+			),
+			mismatches: []LedgerMismatch{
+				{"[TST]", LedgerAccountGang, 200, 225},
+			},
+			unbalanced: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newLedgerTestGame(t)
+
+			err := database.WithTx(g.DbConn, func(tx *sql.Tx) error {
+				return writeLedger(tx, tt.entries)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			mismatches, unbalanced, err := g.Reconcile()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(mismatches, tt.mismatches) {
+				t.Errorf("mismatches %v, want %v", mismatches, tt.mismatches)
+			}
+
+			if unbalanced != tt.unbalanced {
+				t.Errorf("%d unbalanced transactions, want %d", unbalanced, tt.unbalanced)
+			}
+		})
+	}
+}
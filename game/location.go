@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"math/rand"
 	"sync"
+	"time"
 
+	"github.com/mreliasen/swi-server/internal/logger"
 	"github.com/mreliasen/swi-server/internal/responses"
 )
 
@@ -15,6 +17,7 @@ type Location struct {
 	Players     map[*Client]bool
 	Npcs        map[*Entity]bool
 	Items       map[*Item]bool
+	HiddenItems map[*Item]bool // loot stashed away, found with /search
 	PlayerJoin  chan *Client
 	NpcJoin     chan *Entity
 	AddItem     chan *ItemMoved
@@ -22,7 +25,10 @@ type Location struct {
 	Events      chan *ClientResponse
 	Respawn     chan CombatAction
 	Buildings   []*Building
+	Gang        *Gang         // gang controlling the turf
+	Claims      map[*Gang]int // claim points of gangs trying to take the turf
 	mu          sync.Mutex
+	turfMu      sync.Mutex
 }
 
 type Movement struct {
@@ -143,6 +149,7 @@ func (l *Location) run() {
 			}
 
 			client.Player.Mu.Lock()
+			client.Player.SpottedBy = nil
 			client.Player.Loc = l
 			client.Player.LastLocation = Coordinates{
 				North: l.Coords.North,
@@ -159,10 +166,15 @@ func (l *Location) run() {
 				Fled:      fled,
 			})
 
-			// Notify the new location
+			// Notify the new location, hidden players slip by unseen
 			go client.Player.sendGameFrame(false)
 			go client.Player.PlayerSendMapUpdate()
-			l.Events <- &event
+
+			if !client.Player.Hidden {
+				l.Events <- &event
+			}
+
+			go client.Player.aimAtWarEnemies()
 
 			if fled {
 				client.SendEvent(&responses.Generic{
@@ -184,7 +196,7 @@ func (l *Location) run() {
 				})
 			}
 
-			if hasOrigin {
+			if hasOrigin && !client.Player.Hidden {
 				event = CreateEvent(map[uint64]bool{client.Player.PlayerID: true}, &responses.PlayerMoveEvent{
 					Type:      responses.MoveEventType_MOVE_EVENT_LEAVE,
 					Player:    client.Player.PlayerGameFrame(),
@@ -263,6 +275,7 @@ func (l *Location) run() {
 			event := <-l.AddItem
 			l.mu.Lock()
 			event.Item.Loc = l
+			event.Item.DroppedAt = time.Now().Unix()
 			l.Items[event.Item] = true
 			l.mu.Unlock()
 
@@ -294,6 +307,7 @@ func (l *Location) run() {
 			// item picked up
 			event := <-l.RemoveItem
 			if event.Player == nil {
+				l.decayItem(event.Item)
 				continue
 			}
 
@@ -351,11 +365,7 @@ func (l *Location) run() {
 
 				go client.Player.sendGameFrame(false)
 
-				select {
-				case client.Send <- event.Payload:
-				default:
-					delete(l.Players, client)
-				}
+				client.SendEvent(event.Payload)
 			}
 		}
 	}()
@@ -399,6 +409,25 @@ func getFromDirection(from *Location, to *Location) responses.Direction {
 	return responses.Direction_DIRECTION_UNKNOWN
 }
 
+// decayItem removes an item left on the ground for too long.
+func (l *Location) decayItem(item *Item) {
+	l.mu.Lock()
+	if ok := l.Items[item]; !ok {
+		l.mu.Unlock()
+		return
+	}
+
+	delete(l.Items, item)
+	item.Loc = nil
+	l.mu.Unlock()
+
+	logger.LogItems("decay", "decay", item.TemplateName, l.Coords.North, l.Coords.East, l.City.ShortName)
+
+	for client := range l.Players {
+		go client.Player.sendGameFrame(false)
+	}
+}
+
 func CreateLocation(c *City, north int, east int) *Location {
 	loc := Location{
 		City: c,
@@ -410,6 +439,7 @@ func CreateLocation(c *City, north int, east int) *Location {
 		Players:     make(map[*Client]bool),
 		Npcs:        make(map[*Entity]bool),
 		Items:       make(map[*Item]bool),
+		HiddenItems: make(map[*Item]bool),
 		PlayerJoin:  make(chan *Client),
 		NpcJoin:     make(chan *Entity),
 		AddItem:     make(chan *ItemMoved),
@@ -41,7 +41,7 @@ func (e *Entity) OpenDrugDealer(c *Client) {
 	}
 
 	if e.CurrentTarget != nil {
-		c.SendEvent(&responses.Generic{
+		c.Reply(&responses.Generic{
 			Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
 			Messages: []string{fmt.Sprintf("%s is in a fight and ignores you.", e.Name)},
 		})
@@ -31,7 +31,7 @@ func (e *Entity) OpenDruggieMenu(c *Client) {
 	}
 
 	if e.CurrentTarget != nil {
-		c.SendEvent(&responses.Generic{
+		c.Reply(&responses.Generic{
 			Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
 			Messages: []string{fmt.Sprintf("%s is in a fight and ignores you.", e.Name)},
 		})
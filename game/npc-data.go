@@ -59,14 +59,14 @@ func NewNPC(npcType NPCType) *Entity {
 		IsPlayer:      false,
 		NpcTitle:      NpcTemplates[npcType].Title,
 		NpcCommands:   NpcTemplates[npcType].commands,
-		SkillAcc: skills.Accuracy{
-			Value: NpcTemplates[npcType].SkillAcc,
-		},
-		ShoppingWith: make(map[*Entity]int64),
-		NpcHostiles:  make(map[string]bool),
-		TargetedBy:   make(map[*Entity]bool),
+		Skills:        skills.NewSet(),
+		ShoppingWith:  make(map[*Entity]int64),
+		NpcHostiles:   make(map[string]bool),
+		TargetedBy:    make(map[*Entity]bool),
 	}
 
+	npc.Skills.SetValue("accuracy", NpcTemplates[npcType].SkillAcc)
+
 	npc.RandomiseGenderName()
 	npc.Inventory = NewInventory(&npc)
 
@@ -86,13 +86,17 @@ func (n *Entity) NPCFindTarget() (*Entity, bool) {
 		return nil, false
 	}
 
-	if n.CurrentTarget != nil && n.CurrentTarget.IsPlayer {
+	if n.CurrentTarget != nil && n.CurrentTarget.IsPlayer && !n.CurrentTarget.Hidden {
 		if _, ok := n.Loc.Players[n.CurrentTarget.Client]; ok {
 			return n.CurrentTarget, true
 		}
 	}
 
 	for p := range n.Loc.Players {
+		if p.Player.Hidden {
+			continue
+		}
+
 		if _, ok := n.NpcHostiles[p.Player.Client.UUID]; ok {
 			return p.Player, true
 		}
@@ -1,11 +1,14 @@
 package game
 
 import (
+	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/mreliasen/swi-server/game/settings"
 	"github.com/mreliasen/swi-server/game/skills"
+	"github.com/mreliasen/swi-server/internal/database"
 	"github.com/mreliasen/swi-server/internal/database/models"
 	"github.com/mreliasen/swi-server/internal/logger"
 	"github.com/mreliasen/swi-server/internal/responses"
@@ -35,6 +38,14 @@ func (p *Entity) GangTag() string {
 	return p.Gang.Tag
 }
 
+func (p *Entity) gangId() uint64 {
+	if p.Gang == nil {
+		return 0
+	}
+
+	return p.Gang.ID
+}
+
 func (p *Entity) PlayerGetBuildingCommand(cmdKey string) (*Command, bool) {
 	if p.Loc != nil {
 		if val, ok := p.Loc.getBuildingCommand(cmdKey); ok {
@@ -46,6 +57,10 @@ func (p *Entity) PlayerGetBuildingCommand(cmdKey string) (*Command, bool) {
 }
 
 func (p *Entity) PlayerSendMapUpdate() {
+	if !p.Client.HasFeature(FeatureGPS) {
+		return
+	}
+
 	hasItem, _ := p.Inventory.HasItem("smartphone")
 
 	event := responses.GPS{
@@ -62,12 +77,17 @@ func (p *Entity) PlayerSendMapUpdate() {
 		event.Height = int32(p.Loc.City.Height)
 		event.Width = int32(p.Loc.City.Width)
 		event.Pois = pois
+		event.Turf = p.Loc.City.turfFrames(p)
 	}
 
 	p.Client.SendEvent(&event)
 }
 
 func (p *Entity) PlayerSendLocationUpdate() {
+	if !p.Client.HasFeature(FeatureGPS) {
+		return
+	}
+
 	hasItem, _ := p.Inventory.HasItem("smartphone")
 
 	event := responses.GPS{
@@ -79,7 +99,7 @@ func (p *Entity) PlayerSendLocationUpdate() {
 }
 
 func (p *Entity) PlayerSendPlayerList() {
-	for player := range p.Client.Game.Players {
+	for _, player := range p.Client.Game.OnlinePlayers() {
 		event := &responses.PlayerList{
 			Type:     responses.PlayerEvent_EVENT_TYPE_PLAYER_JOIN,
 			Id:       player.Client.UUID,
@@ -88,7 +108,7 @@ func (p *Entity) PlayerSendPlayerList() {
 			GangTag:  player.GangTag(),
 		}
 
-		p.Client.Send <- event
+		p.Client.SendEvent(event)
 	}
 }
 
@@ -103,37 +123,58 @@ func (p *Entity) PlayerSendStatsUpdate() {
 		Hometown:   p.Hometown,
 		Health:     int32(p.Health),
 		MaxHealth:  settings.PlayerMaxHealth,
-		Skills:     []*responses.Skill{},
+		Skills:     p.Skills.Frames(),
 	}
 
 	if p.Rank.NextRank != nil {
 		event.NextRank = p.Rank.NextRank.MinRep - p.Rank.MinRep
 	}
 
-	event.Skills = append(event.Skills, &responses.Skill{
-		Key:   "Accuracy",
-		Value: p.SkillAcc.Value,
-	})
-	/* event.Skills = append(event.Skills, KeyFloatValue{
-		Key:   "Hide",
-		Value: p.SkillHide.Value,
-	})
-	event.Skills = append(event.Skills, KeyFloatValue{
-		Key:   "Snoop",
-		Value: p.SkillSnoop.Value,
-	})
-	event.Skills = append(event.Skills, KeyFloatValue{
-		Key:   "Search",
-		Value: p.SkillSearch.Value,
-	})
-	event.Skills = append(event.Skills, KeyFloatValue{
-		Key:   "Track",
-		Value: p.SkillTrack.Value,
-	}) */
-
 	p.Client.SendEvent(&event)
 }
 
+// SkillReady checks and resets the cooldown on the skill, letting the player know if they have to wait.
+func (p *Entity) SkillReady(skill string) bool {
+	now := time.Now().UnixMilli()
+
+	var delay int64 = settings.PlayerSkillDelayMs
+	if s := skills.Get(skill); s != nil {
+		delay = s.Cooldown()
+	}
+
+	p.Mu.Lock()
+	until := p.LastSkill[skill] + delay
+	if until <= now {
+		p.LastSkill[skill] = now
+	}
+	p.Mu.Unlock()
+
+	if until > now {
+		p.Client.SendEvent(&responses.Generic{
+			Status:   responses.ResponseStatus_RESPONSE_STATUS_NORMAL,
+			Messages: []string{fmt.Sprintf("You must wait another %d seconds before you can do that again.", (until-now)/1000+1)},
+		})
+		return false
+	}
+
+	return true
+}
+
+// SkillCooldown puts the skill on hold for the given time, on top of the regular skill delay.
+// It returns how many milliseconds the player has to wait in total.
+func (p *Entity) SkillCooldown(skill string, ms int64) int64 {
+	var delay int64 = settings.PlayerSkillDelayMs
+	if s := skills.Get(skill); s != nil {
+		delay = s.Cooldown()
+	}
+
+	p.Mu.Lock()
+	p.LastSkill[skill] = time.Now().UnixMilli() + ms
+	p.Mu.Unlock()
+
+	return ms + delay
+}
+
 func (p *Entity) PlayerSendInventoryUpdate() {
 	p.Client.SendEvent(p.Inventory.GameFrame())
 }
@@ -146,7 +187,7 @@ func (p *Entity) sendGameFrame(clearFrame bool) {
 
 	players := []*responses.Player{}
 	for client := range p.Loc.Players {
-		if client.Player.PlayerID == p.PlayerID {
+		if client.Player.PlayerID == p.PlayerID || !p.CanSee(client.Player) {
 			continue
 		}
 
@@ -167,6 +208,7 @@ func (p *Entity) sendGameFrame(clearFrame bool) {
 		Players:     players,
 		Npcs:        npcs,
 		Items:       p.Loc.itemsGameFrames(),
+		GangTag:     p.Loc.TurfGangTag(),
 	}
 
 	p.Client.SendEvent(&frame)
@@ -190,19 +232,26 @@ func CharacterToPlayer(c *models.Character) (*Entity, *Coordinates, error) {
 		PlayerKills:       c.PlayerKills,
 		IsAdmin:           c.IsAdmin == 1,
 		IsPlayer:          true,
-		SkillAcc:          skills.Accuracy{Value: c.SkillAcc},
-		SkillHide:         skills.Hide{Value: c.SkillHide},
-		SkillSearch:       skills.Search{Value: c.SkillSearch},
-		SkillSnoop:        skills.Snoop{Value: c.SkillSnoop},
-		SkillTrack:        skills.Track{Value: c.SkillTrack},
+		Skills:            skills.NewSet(),
 		LastMove:          time.Now().UnixMilli(),
 		ShoppingWith:      make(map[*Entity]int64),
+		LastSkill:         make(map[string]int64),
 		TargetedBy:        make(map[*Entity]bool),
 		AutoAttackEnabled: false,
 		AutoAttackType:    CombatActionPunch,
 	}
 
 	p.Inventory = NewInventory(&p)
+	p.Skills.OnChange = func(_ string, _ float32) {
+		go p.PlayerSendStatsUpdate()
+	}
+
+	// characters from before the skill table was added, keep their skills on the character
+	p.Skills.SetValue("accuracy", c.SkillAcc)
+	p.Skills.SetValue("hide", c.SkillHide)
+	p.Skills.SetValue("search", c.SkillSearch)
+	p.Skills.SetValue("snoop", c.SkillSnoop)
+	p.Skills.SetValue("track", c.SkillTrack)
 
 	lastLocation := Coordinates{
 		North: c.LocationNorth,
@@ -256,53 +305,90 @@ func (e *Entity) StartRoutines() {
 	}()
 }
 
-func (e *Entity) Save() {
+// Save writes the character, skills, inventory and ledger entries in a single transaction.
+func (e *Entity) Save() bool {
 	if !e.IsPlayer {
-		return
+		return false
 	}
 
 	e.Mu.Lock()
-
-	_, err := e.Client.Game.DbConn.Exec(
-		`UPDATE
-            characters
-        SET 
-            reputation = ?,
-            health = ?,
-            npc_kill = ?,
-            player_kills = ?,
-            cash = ?,
-            bank = ?,
-            skill_acc = ?,
-            skill_hide = ?,
-            skill_track = ?,
-            skill_snoop = ?,
-            skill_search = ?,
-            location_n = ?,
-            location_e = ?,
-            location_city = ?
-        WHERE
-            user_id = ?`,
+	character := []any{
 		e.Reputation,
 		e.Health,
 		e.NpcKills,
 		e.PlayerKills,
 		e.Cash,
 		e.Bank,
-		e.SkillAcc.Value,
-		e.SkillHide.Value,
-		e.SkillTrack.Value,
-		e.SkillSnoop.Value,
-		e.SkillSearch.Value,
 		e.LastLocation.North,
 		e.LastLocation.East,
 		e.LastLocation.City,
+		e.gangId(),
 		e.Client.UserId,
-	)
+	}
+	entries := e.pendingLedger()
+	e.Mu.Unlock()
+
+	skillValues := e.Skills.Values()
+
+	e.Inventory.Mu.Lock()
+	inventory, err := e.Inventory.serialize()
+	e.Inventory.Mu.Unlock()
 	if err != nil {
 		print(err.Error())
+		return false
 	}
 
-	e.Mu.Unlock()
-	e.Inventory.save()
+	err = database.WithTx(e.Client.Game.DbConn, func(tx *sql.Tx) error {
+		_, err := tx.Exec(
+			`UPDATE
+                characters
+            SET 
+                reputation = ?,
+                health = ?,
+                npc_kill = ?,
+                player_kills = ?,
+                cash = ?,
+                bank = ?,
+                location_n = ?,
+                location_e = ?,
+                location_city = ?,
+                gang_id = ?
+            WHERE
+                user_id = ?`,
+			character...,
+		)
+		if err != nil {
+			return err
+		}
+
+		for key, value := range skillValues {
+			_, err := tx.Exec(
+				"INSERT OR REPLACE INTO character_skills (character_id, skill, value) VALUES (?, ?, ?)",
+				e.PlayerID,
+				key,
+				value,
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(
+			"INSERT OR REPLACE INTO inventory (user_id, inventory, updated_at) VALUES(?, ?, ?)",
+			e.Client.UserId, inventory, time.Now().Unix(),
+		)
+		if err != nil {
+			return err
+		}
+
+		return writeLedger(tx, entries)
+	})
+	if err != nil {
+		logger.Logger.Error(fmt.Sprintf("Failed to save %s: %s", e.Name, err))
+		return false
+	}
+
+	e.ledgerWritten(len(entries))
+
+	return true
 }
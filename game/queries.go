@@ -1,7 +1,9 @@
 package game
 
 import (
+	"encoding/json"
 	"errors"
+	"strings"
 
 	"github.com/mreliasen/swi-server/internal/database/models"
 	"github.com/mreliasen/swi-server/internal/logger"
@@ -75,6 +77,9 @@ func (g *Game) GetUserCharacter(userId uint64) (*Entity, *Coordinates, error) {
 		return nil, nil, errors.New("failed to load character")
 	}
 
+	g.loadSkills(player)
+	g.openLedger(player)
+
 	if lastLocation.City == "" {
 		lastLocation.City = player.Hometown
 	}
@@ -94,7 +99,6 @@ func (g *Game) GetUserCharacter(userId uint64) (*Entity, *Coordinates, error) {
 		if err != nil {
 			logger.Logger.Error(err.Error())
 		} else {
-			gang.Member = player
 			player.Gang = gang
 		}
 	}
@@ -103,12 +107,21 @@ func (g *Game) GetUserCharacter(userId uint64) (*Entity, *Coordinates, error) {
 }
 
 func (g *Game) GetGang(gangId uint64) (*Gang, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if gang, ok := g.Gangs[gangId]; ok {
+		return gang, nil
+	}
+
 	row := g.DbConn.QueryRow(`
         SELECT
             id,
             name,
             tag,
             leader_id,
+            permissions,
+            bank
         FROM
             gangs 
         WHERE
@@ -127,6 +140,8 @@ func (g *Game) GetGang(gangId uint64) (*Gang, error) {
 		&gangData.Name,
 		&gangData.Tag,
 		&gangData.LeaderID,
+		&gangData.Permissions,
+		&gangData.Bank,
 	)
 
 	if err != nil || gangData.Id == 0 {
@@ -135,11 +150,78 @@ func (g *Game) GetGang(gangId uint64) (*Gang, error) {
 	}
 
 	gang := Gang{
-		ID:       gangData.Id,
-		Name:     gangData.Name,
-		Tag:      gangData.Tag,
-		LeaderID: gangData.LeaderID,
+		ID:          gangData.Id,
+		Name:        gangData.Name,
+		Tag:         gangData.Tag,
+		LeaderID:    gangData.LeaderID,
+		Bank:        gangData.Bank,
+		Permissions: make(map[GangRole]GangPermission),
+		Roles:       make(map[uint64]GangRole),
+		Game:        g,
+	}
+
+	for role, perm := range DefaultGangPermissions {
+		gang.Permissions[role] = perm
+	}
+
+	if gangData.Permissions != "" {
+		if err := json.Unmarshal([]byte(gangData.Permissions), &gang.Permissions); err != nil {
+			logger.Logger.Error(err.Error())
+		}
+	}
+
+	rows, err := g.DbConn.Query("SELECT character_id, gang_id, role FROM gang_members WHERE gang_id = ?", gang.ID)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return nil, errors.New("failed to load gang members")
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		member := models.GangMember{}
+		if err := rows.Scan(&member.CharacterId, &member.GangId, &member.Role); err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		gang.Roles[member.CharacterId] = GangRole(member.Role)
 	}
 
+	gang.loadStash()
+	g.openGangLedger(&gang)
+
+	g.Gangs[gang.ID] = &gang
 	return &gang, nil
 }
+
+func (g *Game) GetGangByTag(tag string) (*Gang, error) {
+	row := g.DbConn.QueryRow("SELECT id FROM gangs WHERE LOWER(tag) = ? LIMIT 1", strings.ToLower(tag))
+
+	gangData := models.Gang{}
+	if err := row.Scan(&gangData.Id); err != nil || gangData.Id == 0 {
+		return nil, errors.New("no gang found")
+	}
+
+	return g.GetGang(gangData.Id)
+}
+
+// loadSkills applies the character's saved skills, skills not saved yet stay at their starting value.
+func (g *Game) loadSkills(p *Entity) {
+	rows, err := g.DbConn.Query("SELECT skill, value FROM character_skills WHERE character_id = ?", p.PlayerID)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		skill := models.CharacterSkill{}
+		if err := rows.Scan(&skill.Skill, &skill.Value); err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		p.Skills.SetValue(skill.Skill, skill.Value)
+	}
+}
@@ -0,0 +1,216 @@
+package game
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/responses"
+)
+
+type RateCategory string
+
+const (
+	RateChat    RateCategory = "chat"
+	RateTrade   RateCategory = "trade"
+	RateMove    RateCategory = "move"
+	RateCombat  RateCategory = "combat"
+	RateGeneral RateCategory = "general"
+)
+
+type RateLimit struct {
+	PerSec float64
+	Burst  float64
+}
+
+var RateLimits = map[RateCategory]RateLimit{
+	RateChat:    {PerSec: settings.RateChatPerSec, Burst: settings.RateChatBurst},
+	RateTrade:   {PerSec: settings.RateTradePerSec, Burst: settings.RateTradeBurst},
+	RateMove:    {PerSec: settings.RateMovePerSec, Burst: settings.RateMoveBurst},
+	RateCombat:  {PerSec: settings.RateCombatPerSec, Burst: settings.RateCombatBurst},
+	RateGeneral: {PerSec: settings.RateGeneralPerSec, Burst: settings.RateGeneralBurst},
+}
+
+// commands not listed here fall under RateGeneral
+var commandCategories = map[string]RateCategory{
+	"/say":        RateChat,
+	"/global":     RateChat,
+	"/pm":         RateChat,
+	"/gc":         RateChat,
+	"/purchase":   RateTrade,
+	"/selldrug":   RateTrade,
+	"/shopbuy":    RateTrade,
+	"/shopsell":   RateTrade,
+	"/buy":        RateTrade,
+	"/sell":       RateTrade,
+	"/transfer":   RateTrade,
+	"/move":       RateMove,
+	"/flee":       RateMove,
+	"/travel":     RateMove,
+	"/aim":        RateCombat,
+	"/unaim":      RateCombat,
+	"/punch":      RateCombat,
+	"/strike":     RateCombat,
+	"/shoot":      RateCombat,
+	"/autoattack": RateCombat,
+}
+
+func CommandCategory(cmdKey string) RateCategory {
+	if category, ok := commandCategories[cmdKey]; ok {
+		return category
+	}
+
+	return RateGeneral
+}
+
+type RateResult int
+
+const (
+	RateAllowed RateResult = iota
+	RateThrottled
+	RateMuted    // still muted from earlier flooding
+	RateMutedNow // muted by this message
+)
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) take(limit RateLimit, now time.Time) bool {
+	b.tokens = min(limit.Burst, b.tokens+now.Sub(b.last).Seconds()*limit.PerSec)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// RateLimiter keeps a token bucket per command category for a client.
+type RateLimiter struct {
+	mu         sync.Mutex
+	buckets    map[RateCategory]*tokenBucket
+	warned     map[RateCategory]time.Time
+	strikes    []time.Time // throttled chat messages within the flood window
+	mutedUntil time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[RateCategory]*tokenBucket),
+		warned:  make(map[RateCategory]time.Time),
+	}
+}
+
+// Take uses up a token in the category. Repeatedly flooding chat mutes the client for ChatMuteSecs.
+func (r *RateLimiter) Take(category RateCategory) RateResult {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+
+	if category == RateChat && now.Before(r.mutedUntil) {
+		return RateMuted
+	}
+
+	bucket, ok := r.buckets[category]
+	if !ok {
+		bucket = &tokenBucket{tokens: RateLimits[category].Burst, last: now}
+		r.buckets[category] = bucket
+	}
+
+	if bucket.take(RateLimits[category], now) {
+		return RateAllowed
+	}
+
+	if category != RateChat {
+		return RateThrottled
+	}
+
+	window := now.Add(-settings.ChatFloodWindowSecs * time.Second)
+	strikes := r.strikes[:0]
+	for _, strike := range r.strikes {
+		if strike.After(window) {
+			strikes = append(strikes, strike)
+		}
+	}
+	r.strikes = append(strikes, now)
+
+	if len(r.strikes) >= settings.ChatFloodStrikes {
+		r.strikes = nil
+		r.mutedUntil = now.Add(settings.ChatMuteSecs * time.Second)
+		return RateMutedNow
+	}
+
+	return RateThrottled
+}
+
+// shouldWarn limits throttle warnings to one a second per category, so they do not become a flood themselves.
+func (r *RateLimiter) shouldWarn(category RateCategory) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.warned[category]) < time.Second {
+		return false
+	}
+
+	r.warned[category] = now
+	return true
+}
+
+// MutedFor returns how many seconds are left of a chat mute.
+func (r *RateLimiter) MutedFor() int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return max(0, int64(time.Until(r.mutedUntil).Seconds()))
+}
+
+// allowCommand applies the rate limit for the command, and lets the client know if it was throttled.
+func (c *Client) allowCommand(cmdKey string) bool {
+	category := CommandCategory(cmdKey)
+	result := c.Limiter.Take(category)
+
+	if result == RateAllowed {
+		return true
+	}
+
+	name := c.UUID
+	if c.Player != nil {
+		name = c.Player.Name
+	}
+
+	if result == RateMutedNow {
+		logger.LogRateLimit(name, string(category), cmdKey, "muted")
+		logger.Logger.Warn(fmt.Sprintf("%s muted for flooding chat.", name))
+	}
+
+	if result == RateMuted || result == RateMutedNow {
+		mutedFor := c.Limiter.MutedFor()
+		if result == RateMutedNow || c.Limiter.shouldWarn(category) {
+			c.Reply(&responses.Generic{
+				Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+				Messages: []string{fmt.Sprintf("You have been muted for flooding the chat, you can talk again in %d seconds.", mutedFor)},
+			})
+		}
+
+		return false
+	}
+
+	logger.LogRateLimit(name, string(category), cmdKey, "throttled")
+
+	if c.Limiter.shouldWarn(category) {
+		c.Reply(&responses.Generic{
+			Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+			Messages: []string{"You are doing that too fast, slow down."},
+		})
+	}
+
+	return false
+}
@@ -0,0 +1,144 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/settings"
+)
+
+func TestTokenBucketTake(t *testing.T) {
+	limit := RateLimit{PerSec: 2, Burst: 3}
+	start := time.Now()
+
+	tests := []struct {
+		name   string
+		tokens float64
+		after  time.Duration // time since the bucket was last used
+		ok     bool
+		left   float64
+	}{
+		{name: "full bucket", tokens: 3, after: 0, ok: true, left: 2},
+		{name: "last token", tokens: 1, after: 0, ok: true, left: 0},
+		{name: "empty bucket", tokens: 0, after: 0, ok: false, left: 0},
+		{name: "partly refilled is not enough", tokens: 0, after: 400 * time.Millisecond, ok: false, left: 0.8},
+		{name: "refills at PerSec", tokens: 0, after: 500 * time.Millisecond, ok: true, left: 0},
+		{name: "refill is capped at Burst", tokens: 0, after: time.Hour, ok: true, left: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &tokenBucket{tokens: tt.tokens, last: start}
+
+			if ok := b.take(limit, start.Add(tt.after)); ok != tt.ok {
+				t.Errorf("take returned %v, want %v", ok, tt.ok)
+			}
+
+			if diff := b.tokens - tt.left; diff > 0.001 || diff < -0.001 {
+				t.Errorf("%.3f tokens left, want %.3f", b.tokens, tt.left)
+			}
+		})
+	}
+}
+
+func TestRateLimiterTake(t *testing.T) {
+	tests := []struct {
+		name     string
+		category RateCategory
+		want     []RateResult // results of the calls after the burst has been used up
+	}{
+		{
+			name:     "other categories are throttled, never muted",
+			category: RateGeneral,
+			want:     repeatResult(RateThrottled, settings.ChatFloodStrikes+1),
+		},
+		{
+			name:     "chat is muted after ChatFloodStrikes",
+			category: RateChat,
+			want:     append(repeatResult(RateThrottled, settings.ChatFloodStrikes-1), RateMutedNow, RateMuted),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRateLimiter()
+			burst := int(RateLimits[tt.category].Burst)
+
+			for i := 0; i < burst+len(tt.want); i++ {
+				got := r.Take(tt.category)
+
+				want := RateAllowed
+				if i >= burst {
+					want = tt.want[i-burst]
+				}
+
+				if got != want {
+					t.Errorf("call %d returned %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func repeatResult(result RateResult, n int) []RateResult {
+	results := make([]RateResult, n)
+	for i := range results {
+		results[i] = result
+	}
+
+	return results
+}
+
+func TestRateLimiterFloodWindow(t *testing.T) {
+	r := NewRateLimiter()
+	for i := 0; i < int(settings.RateChatBurst); i++ {
+		r.Take(RateChat)
+	}
+
+	// strikes from before the window do not count towards a mute
+	old := time.Now().Add(-(settings.ChatFloodWindowSecs + 1) * time.Second)
+	for i := 0; i < settings.ChatFloodStrikes-1; i++ {
+		r.strikes = append(r.strikes, old)
+	}
+
+	if got := r.Take(RateChat); got != RateThrottled {
+		t.Errorf("got %v with expired strikes, want %v", got, RateThrottled)
+	}
+
+	if len(r.strikes) != 1 {
+		t.Errorf("%d strikes kept, want 1", len(r.strikes))
+	}
+
+	// the mute lifts once ChatMuteSecs have passed
+	r.mutedUntil = time.Now().Add(-time.Second)
+	r.buckets[RateChat].tokens = 1
+
+	if got := r.Take(RateChat); got != RateAllowed {
+		t.Errorf("got %v after the mute ended, want %v", got, RateAllowed)
+	}
+
+	if r.MutedFor() != 0 {
+		t.Errorf("still muted for %d seconds", r.MutedFor())
+	}
+}
+
+func TestRateLimiterShouldWarn(t *testing.T) {
+	r := NewRateLimiter()
+
+	if !r.shouldWarn(RateChat) {
+		t.Errorf("first warning was not sent")
+	}
+
+	if r.shouldWarn(RateChat) {
+		t.Errorf("second warning within a second was sent")
+	}
+
+	if !r.shouldWarn(RateTrade) {
+		t.Errorf("warnings are limited across categories")
+	}
+
+	r.warned[RateChat] = time.Now().Add(-time.Second)
+	if !r.shouldWarn(RateChat) {
+		t.Errorf("warning a second later was not sent")
+	}
+}
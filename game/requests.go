@@ -0,0 +1,148 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/requests"
+	"github.com/mreliasen/swi-server/internal/responses"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+var requestDirections = map[requests.Direction]string{
+	requests.Direction_DIRECTION_NORTH: "north",
+	requests.Direction_DIRECTION_SOUTH: "south",
+	requests.Direction_DIRECTION_EAST:  "east",
+	requests.Direction_DIRECTION_WEST:  "west",
+}
+
+var requestAttacks = map[requests.AttackType]string{
+	requests.AttackType_ATTACK_TYPE_PUNCH:  "/punch",
+	requests.AttackType_ATTACK_TYPE_STRIKE: "/strike",
+	requests.AttackType_ATTACK_TYPE_SHOOT:  "/shoot",
+}
+
+// ExecuteRequest decodes an Any wrapped request and runs the command it maps to.
+// Responses sent while the request runs carry its request id.
+func ExecuteRequest(c *Client, wire []byte) {
+	wrapped := &anypb.Any{}
+	if err := proto.Unmarshal(wire, wrapped); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	executeRequest(c, wrapped)
+}
+
+// ExecuteJSONRequest is ExecuteRequest for protojson encoded requests, with the type in "@type", eg.
+// {"@type": "type.googleapis.com/requests.Move", "requestId": "1", "direction": "DIRECTION_NORTH"}
+func ExecuteJSONRequest(c *Client, data []byte) {
+	wrapped := &anypb.Any{}
+	if err := protojson.Unmarshal(data, wrapped); err != nil {
+		invalidRequest(c, err)
+		return
+	}
+
+	executeRequest(c, wrapped)
+}
+
+func invalidRequest(c *Client, err error) {
+	logger.Logger.Trace(fmt.Sprintf("Invalid request: %s", err.Error()))
+	c.Reply(&responses.Generic{
+		Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+		Messages: []string{"Invalid request"},
+	})
+}
+
+func executeRequest(c *Client, wrapped *anypb.Any) {
+	msg, err := wrapped.UnmarshalNew()
+	if err != nil {
+		c.Reply(&responses.Generic{
+			Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+			Messages: []string{"Unknown request"},
+		})
+		return
+	}
+
+	if req, ok := msg.(interface{ GetRequestId() string }); ok {
+		c.requestId = req.GetRequestId()
+		defer func() { c.requestId = "" }()
+	}
+
+	cmdKey, args, ok := requestToCommand(msg)
+	if !ok {
+		c.Reply(&responses.Generic{
+			Status:   responses.ResponseStatus_RESPONSE_STATUS_ERROR,
+			Messages: []string{"Invalid request"},
+		})
+		return
+	}
+
+	if cmdKey == "" {
+		return
+	}
+
+	runCommand(c, cmdKey, args, false)
+}
+
+// requestToCommand maps a typed request onto the command and arguments the text command would use.
+func requestToCommand(msg proto.Message) (string, []string, bool) {
+	switch req := msg.(type) {
+	case *requests.Command:
+		args := strings.Fields(req.Command)
+		if len(args) == 0 {
+			return "", nil, true
+		}
+		return strings.ToLower(args[0]), args[1:], true
+
+	case *requests.Handshake:
+		return "/handshake", append([]string{fmt.Sprintf("%d", req.ClientVersion)}, req.Capabilities...), true
+
+	case *requests.Authenticate:
+		return "/authenticate", []string{req.Email, req.Password}, true
+
+	case *requests.Resume:
+		return "/resume", []string{req.Token}, req.Token != ""
+
+	case *requests.Move:
+		direction, ok := requestDirections[req.Direction]
+		return "/move", []string{direction}, ok
+
+	case *requests.Flee:
+		direction, ok := requestDirections[req.Direction]
+		return "/flee", []string{direction}, ok
+
+	case *requests.Aim:
+		return "/aim", []string{req.Target}, req.Target != ""
+
+	case *requests.Attack:
+		cmdKey, ok := requestAttacks[req.Type]
+		return cmdKey, []string{}, ok
+
+	case *requests.Purchase:
+		return "/purchase", []string{req.MerchantId, fmt.Sprintf("%d", req.Index)}, true
+
+	case *requests.SellDrug:
+		return "/selldrug", []string{req.MerchantId, fmt.Sprintf("%d", req.Index)}, true
+
+	case *requests.ShopBuy:
+		return "/shopbuy", []string{req.BuildingId, fmt.Sprintf("%d", req.Index)}, true
+
+	case *requests.ShopSell:
+		return "/shopsell", []string{req.BuildingId, fmt.Sprintf("%d", req.Index)}, true
+
+	case *requests.PickUp:
+		return "/pickup", []string{req.ItemId}, req.ItemId != ""
+
+	case *requests.Drop:
+		return "/drop", []string{fmt.Sprintf("%d", req.Slot)}, true
+
+	case *requests.Say:
+		return "/say", strings.Fields(req.Message), true
+	}
+
+	return "", nil, false
+}
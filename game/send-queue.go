@@ -0,0 +1,164 @@
+package game
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/responses"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type SendPolicy int
+
+const (
+	SendAlways    SendPolicy = iota // never dropped, like chat, trades and news
+	SendDroppable                   // dropped first when the queue is full
+	SendLatest                      // snapshots, only the newest queued one is sent
+)
+
+func sendPolicy(msg protoreflect.ProtoMessage) SendPolicy {
+	switch msg.(type) {
+	case *responses.Location, *responses.Stats, *responses.Inventory, *responses.GPS, *responses.MerchantInventory:
+		return SendLatest
+	case *responses.PlayerMoveEvent, *responses.NPCMoveEvent:
+		return SendDroppable
+	}
+
+	return SendAlways
+}
+
+// SendQueue is a bounded ring buffer of messages waiting to be written to a client.
+type SendQueue struct {
+	mu        sync.Mutex
+	buf       []protoreflect.ProtoMessage
+	head      int
+	size      int
+	closed    bool
+	fullSince time.Time
+	peak      int
+	dropped   uint64
+	Ready     chan struct{} // signalled when messages are queued or the queue is closed
+}
+
+func NewSendQueue(capacity int) *SendQueue {
+	return &SendQueue{
+		buf:   make([]protoreflect.ProtoMessage, capacity),
+		Ready: make(chan struct{}, 1),
+	}
+}
+
+func (q *SendQueue) at(i int) int {
+	return (q.head + i) % len(q.buf)
+}
+
+func (q *SendQueue) signal() {
+	select {
+	case q.Ready <- struct{}{}:
+	default:
+	}
+}
+
+// Push queues the message according to its drop policy. It returns false when the client
+// cannot keep up, either because a message which is never dropped does not fit or the
+// queue has been backed up for longer than SlowClientSecs.
+func (q *SendQueue) Push(msg protoreflect.ProtoMessage) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return true
+	}
+
+	policy := sendPolicy(msg)
+
+	// a newer snapshot replaces the queued one
+	if policy == SendLatest {
+		name := msg.ProtoReflect().Descriptor().FullName()
+		for i := 0; i < q.size; i++ {
+			if queued := q.buf[q.at(i)]; queued.ProtoReflect().Descriptor().FullName() == name {
+				q.buf[q.at(i)] = msg
+				q.dropped++
+				return true
+			}
+		}
+	}
+
+	if q.size >= len(q.buf)/2 {
+		if q.fullSince.IsZero() {
+			q.fullSince = time.Now()
+		} else if time.Since(q.fullSince) > settings.SlowClientSecs*time.Second {
+			return false
+		}
+	}
+
+	if q.size == len(q.buf) && !q.dropOldest() {
+		if policy == SendAlways {
+			return false
+		}
+
+		q.dropped++
+		return true
+	}
+
+	q.buf[q.at(q.size)] = msg
+	q.size++
+	q.peak = max(q.peak, q.size)
+	q.signal()
+	return true
+}
+
+// dropOldest removes the oldest droppable message to make room, if there is one.
+func (q *SendQueue) dropOldest() bool {
+	for i := 0; i < q.size; i++ {
+		if sendPolicy(q.buf[q.at(i)]) == SendAlways {
+			continue
+		}
+
+		for j := i; j < q.size-1; j++ {
+			q.buf[q.at(j)] = q.buf[q.at(j+1)]
+		}
+
+		q.size--
+		q.buf[q.at(q.size)] = nil
+		q.dropped++
+		return true
+	}
+
+	return false
+}
+
+// Drain takes all queued messages, and reports if the queue has been closed.
+func (q *SendQueue) Drain() ([]protoreflect.ProtoMessage, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	msgs := make([]protoreflect.ProtoMessage, q.size)
+	for i := 0; i < q.size; i++ {
+		msgs[i] = q.buf[q.at(i)]
+		q.buf[q.at(i)] = nil
+	}
+
+	q.head = 0
+	q.size = 0
+	q.fullSince = time.Time{}
+
+	return msgs, q.closed
+}
+
+// Close stops the queue accepting messages and wakes up the writer.
+func (q *SendQueue) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.closed = true
+	q.signal()
+}
+
+// Stats returns the current queue depth, the deepest it has been and how many messages were dropped.
+func (q *SendQueue) Stats() (int, int, uint64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.size, q.peak, q.dropped
+}
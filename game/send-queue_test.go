@@ -0,0 +1,225 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mreliasen/swi-server/internal/responses"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+func chat(text string) protoreflect.ProtoMessage {
+	return &responses.Generic{Messages: []string{text}}
+}
+
+func move(name string) protoreflect.ProtoMessage {
+	return &responses.PlayerMoveEvent{Player: &responses.Player{Name: name}}
+}
+
+func news(text string) protoreflect.ProtoMessage {
+	return &responses.NewsFlash{Msg: text}
+}
+
+func stats(name string) protoreflect.ProtoMessage {
+	return &responses.Stats{Name: name}
+}
+
+// queued names the messages so the order can be compared.
+func queued(msgs []protoreflect.ProtoMessage) []string {
+	names := []string{}
+	for _, msg := range msgs {
+		switch m := msg.(type) {
+		case *responses.Generic:
+			names = append(names, "chat:"+m.Messages[0])
+		case *responses.PlayerMoveEvent:
+			names = append(names, "move:"+m.Player.Name)
+		case *responses.NewsFlash:
+			names = append(names, "news:"+m.Msg)
+		case *responses.Stats:
+			names = append(names, "stats:"+m.Name)
+		}
+	}
+
+	return names
+}
+
+func TestSendQueuePush(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		head     int // where the ring buffer starts, to cover wrapping around the end
+		pushes   []protoreflect.ProtoMessage
+		ok       bool // result of the last push
+		want     []string
+		dropped  uint64
+	}{
+		{
+			name:     "keeps order",
+			capacity: 4,
+			pushes:   []protoreflect.ProtoMessage{chat("a"), move("b"), chat("c")},
+			ok:       true,
+			want:     []string{"chat:a", "move:b", "chat:c"},
+		},
+		{
+			name:     "wraps around the end of the buffer",
+			capacity: 4,
+			head:     3,
+			pushes:   []protoreflect.ProtoMessage{chat("a"), chat("b"), chat("c"), chat("d")},
+			ok:       true,
+			want:     []string{"chat:a", "chat:b", "chat:c", "chat:d"},
+		},
+		{
+			name:     "newer snapshot replaces the queued one",
+			capacity: 4,
+			pushes:   []protoreflect.ProtoMessage{stats("old"), chat("a"), stats("new")},
+			ok:       true,
+			want:     []string{"stats:new", "chat:a"},
+			dropped:  1,
+		},
+		{
+			name:     "snapshot replaced across the wrap",
+			capacity: 4,
+			head:     2,
+			pushes:   []protoreflect.ProtoMessage{chat("a"), chat("b"), stats("old"), stats("new")},
+			ok:       true,
+			want:     []string{"chat:a", "chat:b", "stats:new"},
+			dropped:  1,
+		},
+		{
+			name:     "full queue drops the oldest droppable message",
+			capacity: 3,
+			pushes:   []protoreflect.ProtoMessage{chat("a"), move("b"), move("c"), chat("d")},
+			ok:       true,
+			want:     []string{"chat:a", "move:c", "chat:d"},
+			dropped:  1,
+		},
+		{
+			name:     "full queue drops the oldest droppable message across the wrap",
+			capacity: 3,
+			head:     2,
+			pushes:   []protoreflect.ProtoMessage{chat("a"), move("b"), chat("c"), chat("d")},
+			ok:       true,
+			want:     []string{"chat:a", "chat:c", "chat:d"},
+			dropped:  1,
+		},
+		{
+			name:     "droppable message is dropped when nothing else can be",
+			capacity: 2,
+			pushes:   []protoreflect.ProtoMessage{chat("a"), chat("b"), move("c")},
+			ok:       true,
+			want:     []string{"chat:a", "chat:b"},
+			dropped:  1,
+		},
+		{
+			name:     "news is never dropped to make room",
+			capacity: 3,
+			pushes:   []protoreflect.ProtoMessage{news("a"), move("b"), chat("c"), news("d")},
+			ok:       true,
+			want:     []string{"news:a", "chat:c", "news:d"},
+			dropped:  1,
+		},
+		{
+			name:     "news fails like chat when nothing can be dropped",
+			capacity: 2,
+			pushes:   []protoreflect.ProtoMessage{news("a"), chat("b"), news("c")},
+			ok:       false,
+			want:     []string{"news:a", "chat:b"},
+		},
+		{
+			name:     "message which is never dropped fails when nothing can be dropped",
+			capacity: 2,
+			pushes:   []protoreflect.ProtoMessage{chat("a"), chat("b"), chat("c")},
+			ok:       false,
+			want:     []string{"chat:a", "chat:b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewSendQueue(tt.capacity)
+			q.head = tt.head
+
+			ok := true
+			for _, msg := range tt.pushes {
+				ok = q.Push(msg)
+			}
+
+			if ok != tt.ok {
+				t.Errorf("last push returned %v, want %v", ok, tt.ok)
+			}
+
+			_, _, dropped := q.Stats()
+			if dropped != tt.dropped {
+				t.Errorf("dropped %d messages, want %d", dropped, tt.dropped)
+			}
+
+			msgs, closed := q.Drain()
+			if closed {
+				t.Errorf("queue reported closed")
+			}
+
+			if got := queued(msgs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("queued %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSendQueueSlowClient(t *testing.T) {
+	q := NewSendQueue(4)
+	q.Push(chat("a"))
+	q.Push(chat("b"))
+
+	// backed up past half capacity for longer than SlowClientSecs
+	q.fullSince = time.Now().Add(-time.Hour)
+
+	if q.Push(chat("c")) {
+		t.Errorf("push to a queue backed up for too long succeeded")
+	}
+
+	// draining resets the backed up timer
+	q.Drain()
+	q.Push(chat("a"))
+	q.Push(chat("b"))
+
+	if !q.Push(chat("c")) {
+		t.Errorf("push after draining failed")
+	}
+}
+
+func TestSendQueueDrainAndClose(t *testing.T) {
+	q := NewSendQueue(4)
+	q.Push(chat("a"))
+	q.Push(stats("s"))
+
+	_, peak, _ := q.Stats()
+	if peak != 2 {
+		t.Errorf("peak %d, want 2", peak)
+	}
+
+	select {
+	case <-q.Ready:
+	default:
+		t.Errorf("push did not signal Ready")
+	}
+
+	msgs, closed := q.Drain()
+	if got := queued(msgs); !reflect.DeepEqual(got, []string{"chat:a", "stats:s"}) || closed {
+		t.Errorf("drained %v (closed %v)", got, closed)
+	}
+
+	if msgs, _ := q.Drain(); len(msgs) != 0 {
+		t.Errorf("second drain returned %d messages", len(msgs))
+	}
+
+	q.Close()
+	if !q.Push(chat("late")) {
+		t.Errorf("push to a closed queue should be ignored, not fail")
+	}
+
+	msgs, closed = q.Drain()
+	if len(msgs) != 0 || !closed {
+		t.Errorf("drain after close returned %d messages (closed %v)", len(msgs), closed)
+	}
+}
@@ -0,0 +1,205 @@
+package game
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/responses"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// newSessionKey generates the key session tokens are signed with. Sessions can only be
+// resumed while the player is still in memory, so the key does not need to survive a restart.
+func newSessionKey() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		logger.Logger.Fatal(fmt.Sprintf("Failed to generate session key: %s", err))
+	}
+
+	return key
+}
+
+func (g *Game) signSession(payload string) string {
+	mac := hmac.New(sha256.New, g.sessionKey)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// SessionToken creates a signed token for the client's session, which expires after SessionTokenHours.
+func (g *Game) SessionToken(c *Client) (string, int64) {
+	expires := time.Now().Add(settings.SessionTokenHours * time.Hour).Unix()
+	payload := fmt.Sprintf("%d:%s:%d", c.UserId, c.UUID, expires)
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+
+	return encoded + "." + g.signSession(encoded), expires
+}
+
+// verifySession checks the token signature and expiry, and returns the user and session it belongs to.
+func (g *Game) verifySession(token string) (uint64, string, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return 0, "", errors.New("malformed session token")
+	}
+
+	if !hmac.Equal([]byte(signature), []byte(g.signSession(encoded))) {
+		return 0, "", errors.New("invalid session token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, "", errors.New("malformed session token")
+	}
+
+	parts := strings.Split(string(payload), ":")
+	if len(parts) != 3 {
+		return 0, "", errors.New("malformed session token")
+	}
+
+	userId, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return 0, "", errors.New("malformed session token")
+	}
+
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, "", errors.New("malformed session token")
+	}
+
+	if expires < time.Now().Unix() {
+		return 0, "", errors.New("session token expired")
+	}
+
+	return userId, parts[1], nil
+}
+
+// SendSessionToken issues the client a new session token it can /resume with after a disconnect.
+func (g *Game) SendSessionToken(c *Client) {
+	token, expires := g.SessionToken(c)
+
+	session, err := anypb.New(&responses.Session{
+		Token:   token,
+		Expires: expires,
+	})
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	c.SendEvent(&responses.System{
+		Type:     responses.SystemType_SESSION_TOKEN,
+		AnyField: session,
+	})
+}
+
+// DisconnectedPlayer returns the user's player if it is still in-game waiting for the grace window to run out.
+func (g *Game) DisconnectedPlayer(userId uint64) *Entity {
+	g.mu.Lock()
+	candidates := []*Entity{}
+	for p := range g.Players {
+		if p.Client != nil && p.Client.UserId == userId {
+			candidates = append(candidates, p)
+		}
+	}
+	g.mu.Unlock()
+
+	for _, p := range candidates {
+		p.Client.Mu.Lock()
+		waiting := p.Client.Connection == nil && !p.Client.loggedOut && !p.Client.resumed
+		p.Client.Mu.Unlock()
+
+		if waiting {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// ResumeSession reattaches the player behind the token to the client, without logging them out and back in.
+func (g *Game) ResumeSession(c *Client, token string) error {
+	userId, sessionId, err := g.verifySession(token)
+	if err != nil {
+		return err
+	}
+
+	p := g.DisconnectedPlayer(userId)
+	if p == nil || p.Client.UUID != sessionId {
+		return errors.New("no session to resume")
+	}
+
+	if !g.ResumePlayer(c, p) {
+		return errors.New("no session to resume")
+	}
+
+	return nil
+}
+
+// ResumePlayer moves a disconnected player over to the new client.
+func (g *Game) ResumePlayer(c *Client, p *Entity) bool {
+	old := p.Client
+
+	old.Mu.Lock()
+	if old.Connection != nil || old.loggedOut || old.resumed {
+		old.Mu.Unlock()
+		return false
+	}
+	old.resumed = true
+	old.Mu.Unlock()
+
+	c.Mu.Lock()
+	c.Authenticated = true
+	c.UserId = old.UserId
+	c.UserType = old.UserType
+	c.Verified = old.Verified
+	c.UUID = old.UUID
+	c.Limiter = old.Limiter // a reconnect does not reset a mute
+	c.Player = p
+	c.Mu.Unlock()
+
+	p.Mu.Lock()
+	p.Client = c
+	p.Mu.Unlock()
+
+	if loc := p.Loc; loc != nil {
+		loc.mu.Lock()
+		delete(loc.Players, old)
+		loc.Players[c] = true
+		loc.mu.Unlock()
+
+		loc.City.Mu.Lock()
+		delete(loc.City.Players, old)
+		loc.City.Players[c] = true
+		loc.City.Mu.Unlock()
+	}
+
+	g.Register <- c
+
+	c.SendEvent(&responses.System{
+		Type: responses.SystemType_GAME_READY,
+	})
+
+	g.SendSessionToken(c)
+
+	go p.PlayerSendInventoryUpdate()
+	go p.PlayerSendStatsUpdate()
+	go p.PlayerSendPlayerList()
+	go p.PlayerSendMapUpdate()
+	go p.sendGameFrame(true)
+
+	c.SendEvent(&responses.Generic{
+		Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+		Messages: []string{"Welcome back, your session has been resumed."},
+	})
+
+	logger.Logger.Info(fmt.Sprintf("%s resumed their session.", p.Name))
+	return true
+}
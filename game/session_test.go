@@ -0,0 +1,142 @@
+package game
+
+import (
+	"encoding/base64"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/skills"
+)
+
+// newSessionTestGame gives the game a player whose connection dropped, waiting to be resumed.
+func newSessionTestGame(t *testing.T) (*Game, *Entity) {
+	quietLogger()
+
+	g := &Game{
+		Players:    make(map[*Entity]bool),
+		Register:   make(chan *Client, 1),
+		sessionKey: newSessionKey(),
+	}
+
+	old := newSessionTestClient(g, nil, "old-session")
+	old.Authenticated = true
+	old.UserId = 7
+
+	p := &Entity{PlayerID: 1, Name: "Tester", Client: old, Rank: &Rank{Name: "Thug"}, Skills: skills.NewSet()}
+	p.Inventory = NewInventory(p)
+	old.Player = p
+	g.Players[p] = true
+
+	return g, p
+}
+
+func newSessionTestClient(g *Game, conn Transport, sessionId string) *Client {
+	return &Client{
+		Game:       g,
+		Connection: conn,
+		UUID:       sessionId,
+		Send:       NewSendQueue(64),
+		Limiter:    NewRateLimiter(),
+	}
+}
+
+func signedSession(g *Game, userId uint64, sessionId string, expires int64) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s:%d", userId, sessionId, expires)))
+	return encoded + "." + g.signSession(encoded)
+}
+
+func TestResumeSession(t *testing.T) {
+	g, p := newSessionTestGame(t)
+	old := p.Client
+	token, _ := g.SessionToken(old)
+
+	c := newSessionTestClient(g, &telnetTransport{}, "new-session")
+	if err := g.ResumeSession(c, token); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Player != p || p.Client != c {
+		t.Errorf("player was not moved over to the new client")
+	}
+
+	if !c.Authenticated || c.UserId != old.UserId || c.UUID != old.UUID {
+		t.Errorf("new client did not take over the session")
+	}
+
+	if c.Limiter != old.Limiter {
+		t.Errorf("rate limits were reset by the resume")
+	}
+
+	select {
+	case registered := <-g.Register:
+		if registered != c {
+			t.Errorf("wrong client registered")
+		}
+	default:
+		t.Errorf("new client was not registered")
+	}
+
+	// the token cannot take the session again from another connection
+	if err := g.ResumeSession(newSessionTestClient(g, &telnetTransport{}, "third-session"), token); err == nil {
+		t.Errorf("resumed a session which was already resumed")
+	}
+}
+
+func TestResumeSessionRejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		userId   uint64
+		session  string
+		expires  time.Duration
+		otherKey bool // signed with the key of another server or an earlier run
+		tamper   bool
+		setup    func(old *Client)
+	}{
+		{name: "tampered token", userId: 7, session: "old-session", expires: time.Hour, tamper: true},
+		{name: "signed with another key", userId: 7, session: "old-session", expires: time.Hour, otherKey: true},
+		{name: "expired token", userId: 7, session: "old-session", expires: -time.Minute},
+		{name: "another session of the user", userId: 7, session: "other-session", expires: time.Hour},
+		{name: "another user", userId: 8, session: "old-session", expires: time.Hour},
+		{
+			name: "player still connected", userId: 7, session: "old-session", expires: time.Hour,
+			setup: func(old *Client) { old.Connection = &telnetTransport{} },
+		},
+		{
+			name: "player logged out", userId: 7, session: "old-session", expires: time.Hour,
+			setup: func(old *Client) { old.loggedOut = true },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, p := newSessionTestGame(t)
+			old := p.Client
+			if tt.setup != nil {
+				tt.setup(old)
+			}
+
+			signer := g
+			if tt.otherKey {
+				signer = &Game{sessionKey: newSessionKey()}
+			}
+
+			token := signedSession(signer, tt.userId, tt.session, time.Now().Add(tt.expires).Unix())
+			// swap the first character of the payload for a different one
+			if tt.tamper && token[0] == 'A' {
+				token = "B" + token[1:]
+			} else if tt.tamper {
+				token = "A" + token[1:]
+			}
+
+			c := newSessionTestClient(g, &telnetTransport{}, "new-session")
+			if err := g.ResumeSession(c, token); err == nil {
+				t.Fatalf("session was resumed")
+			}
+
+			if p.Client != old || c.Player != nil || c.Authenticated {
+				t.Errorf("failed resume changed the session")
+			}
+		})
+	}
+}
@@ -4,17 +4,53 @@ import "time"
 
 const (
 	// websocket settings
-	WriteWait      = 10 * time.Second
-	PongWait       = 60 * time.Second
-	PingPeriod     = (PongWait * 9) / 10
-	MaxMessageSize = 256
+	WriteWait          = 10 * time.Second
+	PongWait           = 60 * time.Second
+	PingPeriod         = (PongWait * 9) / 10
+	MaxMessageSize     = 256
+	MaxJSONMessageSize = 1024 // JSON requests are more verbose than protobuf
+	SendQueueSize      = 256  // messages queued per client before dropping
+	SlowClientSecs     = 15   // how long a client's queue can stay backed up before it is disconnected
+	BatchWindowMs      = 20   // default time to collect messages following a write before the next one
+	MaxFrameSize       = 16 * 1024
+
+	// telnet gateway
+	TelnetIdleMinutes = 30
+
+	// protocol
+	ProtocolVersion    = 3            // from 3, clients which handshake get length-prefixed frames
+	ClientVersionCheck = 202310082253 // oldest client version accepted
+	RequireHandshake   = false        // refuse commands from clients which have not sent a handshake
 
 	// Misc settings
-	// ClientVersionCheck = 202310082253
 	// MaxPlayers         = 100
 	AutoSaveMinutes   = 5
 	CombatLoggingSecs = 10
 
+	// rate limits, commands per second and how many can be sent in a burst
+	RateChatPerSec      = 1
+	RateChatBurst       = 5
+	RateTradePerSec     = 4
+	RateTradeBurst      = 10
+	RateMovePerSec      = 8
+	RateMoveBurst       = 12
+	RateCombatPerSec    = 5
+	RateCombatBurst     = 10
+	RateGeneralPerSec   = 5
+	RateGeneralBurst    = 15
+	ChatFloodStrikes    = 5 // throttled chat messages within the window before being muted
+	ChatFloodWindowSecs = 30
+	ChatMuteSecs        = 120
+
+	// sessions
+	SessionTokenHours = 24
+	ResumeGraceSecs   = 30 // how long a disconnected player stays in-game to /resume
+
+	// accounts
+	VerifyTokenHours        = 48
+	ResetTokenMinutes       = 60
+	AccountMailCooldownSecs = 60 // how often a verification or reset email can be requested
+
 	// commands / actions
 	HealCostPerPoint  = 30
 	DrinkRepGain      = 5
@@ -35,11 +71,13 @@ const (
 	NPCMoveMinDelaySeconds    = 30
 	NPCAttackDelayMs          = 2250
 	DroppedItemsDecaySeconds  = 60 * 60
+	DroppedItemsSweepSeconds  = 60
 	TravelCostChangeMinutes   = 60
 
 	// skills
 	PlayerAttackDelayMs = 2200
 	PlayerSkillDelayMs  = 2200
+	SearchFailDelayMs   = 10000
 	PlayerMoveDelayMs   = 150
 
 	// General Player Stats and timers
@@ -50,6 +88,32 @@ const (
 	DrugRepIncrease        = 3
 	ItemSellPriceLoss      = 0.65
 
+	// Gangs
+	GangCreateCost     = 25000
+	GangMaxMembers     = 20
+	GangChatBacklog    = 50
+	GangChatRetainDays = 14
+
+	// Turf
+	TurfClaimPoints      = 100
+	TurfPresencePoints   = 5 // per member a gang outnumbers its rivals by, where rival gangs meet
+	TurfPresenceTickSecs = 60
+	TurfKillNpcPoints    = 10
+	TurfKillPlayerPoints = 25
+	TurfDrugSalesCut     = 0.10
+
+	// Hidden loot
+	HiddenLootSpawnMinutes = 5
+	HiddenLootMaxPerCity   = 10
+	SearchLootChance       = 35
+
+	// Gang wars
+	GangWarDurationHours = 24
+	GangWarWinRep        = 500
+	GangWarRepPerKill    = 50
+	GangWarCheckSecs     = 60
+	GangWarCooldownHours = 12 // before the same two gangs can go to war again
+
 	// New players
 	PlayerStartCash        = 100
 	PlayerStartBank        = 500
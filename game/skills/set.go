@@ -0,0 +1,146 @@
+package skills
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/mreliasen/swi-server/internal/responses"
+)
+
+// Set holds an entity's skill values.
+type Set struct {
+	mu      sync.Mutex
+	values  map[string]float32
+	lastUse map[string]int64
+	repeats map[string]int
+	// called whenever training changes a skill
+	OnChange func(key string, value float32)
+}
+
+// NewSet creates a set with every skill at its starting value.
+func NewSet() *Set {
+	s := &Set{
+		values:  make(map[string]float32),
+		lastUse: make(map[string]int64),
+		repeats: make(map[string]int),
+	}
+
+	for key, skill := range Skills {
+		s.values[key] = skill.Start
+	}
+
+	return s
+}
+
+func (s *Set) Value(key string) float32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.values[key]
+}
+
+func (s *Set) SetValue(key string, value float32) {
+	s.mu.Lock()
+	s.values[key] = value
+	s.mu.Unlock()
+}
+
+// Values returns a copy of every skill value, keyed by skill.
+func (s *Set) Values() map[string]float32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[string]float32, len(s.values))
+	for key, value := range s.values {
+		values[key] = value
+	}
+
+	return values
+}
+
+// Scale multiplies every skill by the factor, eg. as a death penalty.
+func (s *Set) Scale(factor float32) {
+	s.mu.Lock()
+	for key := range s.values {
+		s.values[key] *= factor
+	}
+	s.mu.Unlock()
+}
+
+// Check rolls against the skill and trains it from the outcome.
+func (s *Set) Check(key string) bool {
+	skill := Get(key)
+	if skill == nil {
+		return false
+	}
+
+	success := s.Roll(key)
+	s.Train(skill, success)
+	return success
+}
+
+// Roll rolls against the skill without training it, for when the skill is tested by someone else.
+func (s *Set) Roll(key string) bool {
+	v := float32(rand.Intn(10001)) / float32(100)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return v <= s.values[key]
+}
+
+// Train raises the skill by its curve, less for failures and repeated use.
+func (s *Set) Train(skill *Skill, success bool) {
+	now := time.Now().UnixMilli()
+
+	s.mu.Lock()
+
+	value := s.values[skill.Key]
+	amount := skill.gain(value)
+
+	if !success {
+		if value > skill.FailCap {
+			amount = 0
+		} else {
+			amount *= skill.FailGain
+		}
+	}
+
+	if skill.RepeatWindowMs > 0 {
+		if now-s.lastUse[skill.Key] < skill.RepeatWindowMs {
+			s.repeats[skill.Key] += 1
+		} else {
+			s.repeats[skill.Key] = 0
+		}
+
+		for i := 0; i < s.repeats[skill.Key]; i++ {
+			amount *= skill.RepeatFactor
+		}
+	}
+
+	s.lastUse[skill.Key] = now
+	s.values[skill.Key] = value + amount
+	value = s.values[skill.Key]
+
+	s.mu.Unlock()
+
+	if amount > 0 && s.OnChange != nil {
+		s.OnChange(skill.Key, value)
+	}
+}
+
+// Frames returns the skills as they are shown to the player.
+func (s *Set) Frames() []*responses.Skill {
+	values := s.Values()
+	frames := []*responses.Skill{}
+
+	for _, skill := range Ordered() {
+		frames = append(frames, &responses.Skill{
+			Key:   skill.Name,
+			Value: values[skill.Key],
+		})
+	}
+
+	return frames
+}
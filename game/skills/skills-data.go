@@ -0,0 +1,79 @@
+package skills
+
+import "github.com/mreliasen/swi-server/game/settings"
+
+// the shared curve for the stealth and tracking skills
+var stealthCurve = []Step{
+	{10, 1},
+	{20, 0.8},
+	{30, 0.4},
+	{40, 0.05},
+	{50, 0.02},
+	{60, 0.008},
+	{70, 0.005},
+	{80, 0.002},
+	{90, 0.0008},
+	{100, 0.0005},
+}
+
+var Skills = map[string]*Skill{
+	"accuracy": {
+		Key:   "accuracy",
+		Name:  "Accuracy",
+		Order: 1,
+		Start: settings.PlayerStartSkillAcc,
+		Curve: []Step{
+			{20, 1},
+			{30, 0.2},
+			{40, 0.08},
+			{50, 0.04},
+			{60, 0.008},
+			{70, 0.004},
+			{80, 0.0008},
+			{90, 0.0004},
+			{100, 0.00008},
+		},
+		FailGain: 1.0 / 15,
+		FailCap:  35,
+	},
+	"track": {
+		Key:            "track",
+		Name:           "Track",
+		Order:          2,
+		Start:          settings.PlayerStartSkillTrack,
+		Curve:          stealthCurve,
+		CooldownMs:     settings.PlayerSkillDelayMs,
+		RepeatWindowMs: 60000,
+		RepeatFactor:   0.8,
+	},
+	"hide": {
+		Key:            "hide",
+		Name:           "Hide",
+		Order:          3,
+		Start:          settings.PlayerStartSkillHide,
+		Curve:          stealthCurve,
+		CooldownMs:     settings.PlayerSkillDelayMs,
+		RepeatWindowMs: 60000,
+		RepeatFactor:   0.8,
+	},
+	"search": {
+		Key:            "search",
+		Name:           "Search",
+		Order:          4,
+		Start:          settings.PlayerStartSkillSearch,
+		Curve:          stealthCurve,
+		CooldownMs:     settings.PlayerSkillDelayMs,
+		RepeatWindowMs: 60000,
+		RepeatFactor:   0.8,
+	},
+	"snoop": {
+		Key:            "snoop",
+		Name:           "Snoop",
+		Order:          5,
+		Start:          settings.PlayerStartSkillSnoop,
+		Curve:          stealthCurve,
+		CooldownMs:     settings.PlayerSkillDelayMs,
+		RepeatWindowMs: 60000,
+		RepeatFactor:   0.8,
+	},
+}
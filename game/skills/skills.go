@@ -1,6 +1,72 @@
 package skills
 
-type Skill interface {
-	Train()
-	SkillCheck() bool
+import (
+	"sort"
+
+	"github.com/mreliasen/swi-server/game/settings"
+)
+
+// Step is a row in a skill's training curve. While the skill is below the
+// value, every successful use trains it by the gain.
+type Step struct {
+	Below float32
+	Gain  float32
+}
+
+// Skill describes how a skill trains. Skills are looked up by key, so adding a
+// new one only needs an entry in Skills.
+type Skill struct {
+	Key   string
+	Name  string
+	Order int
+	Start float32
+	Curve []Step
+	// share of the gain given on a failed check, for skills you learn from your mistakes
+	FailGain float32
+	// failed checks stop training once the skill is above this value
+	FailCap float32
+	// how long the player has to wait between uses, 0 means the skill is not on a cooldown
+	CooldownMs int64
+	// every use within the window of the last one trains the skill a little less
+	RepeatWindowMs int64
+	RepeatFactor   float32
+}
+
+// Get returns the skill with the given key, or nil if there is no such skill.
+func Get(key string) *Skill {
+	return Skills[key]
+}
+
+// Ordered returns every skill in the order they are shown to players.
+func Ordered() []*Skill {
+	list := make([]*Skill, 0, len(Skills))
+	for _, s := range Skills {
+		list = append(list, s)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Order < list[j].Order
+	})
+
+	return list
+}
+
+// Cooldown is how long the player has to wait between uses of the skill.
+func (s *Skill) Cooldown() int64 {
+	if s.CooldownMs > 0 {
+		return s.CooldownMs
+	}
+
+	return settings.PlayerSkillDelayMs
+}
+
+// gain looks up how much a successful use trains the skill at the given value.
+func (s *Skill) gain(value float32) float32 {
+	for _, step := range s.Curve {
+		if value < step.Below {
+			return step.Gain
+		}
+	}
+
+	return 0
 }
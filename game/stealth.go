@@ -0,0 +1,141 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/responses"
+)
+
+// CanSee reports whether the player can see the other player at their location.
+func (p *Entity) CanSee(other *Entity) bool {
+	other.Mu.Lock()
+	defer other.Mu.Unlock()
+
+	return !other.Hidden || other.SpottedBy[p]
+}
+
+// Hide takes the player out of sight of everyone at the location.
+func (p *Entity) Hide() {
+	p.Mu.Lock()
+	p.Hidden = true
+	p.SpottedBy = nil
+	p.Mu.Unlock()
+
+	p.refreshLocationFrames()
+}
+
+// Reveal brings a hidden player back into sight, it is a no-op if they are not hidden.
+func (p *Entity) Reveal() {
+	p.Mu.Lock()
+	if !p.Hidden {
+		p.Mu.Unlock()
+		return
+	}
+
+	p.Hidden = false
+	p.SpottedBy = nil
+	p.Mu.Unlock()
+
+	if p.Client != nil {
+		p.Client.SendEvent(&responses.Generic{
+			Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+			Messages: []string{"You step out of the shadows, you are no longer hidden."},
+		})
+	}
+
+	if p.Loc == nil {
+		return
+	}
+
+	p.refreshLocationFrames()
+
+	event := CreateEvent(map[uint64]bool{p.PlayerID: true}, &responses.Generic{
+		Status:   responses.ResponseStatus_RESPONSE_STATUS_INFO,
+		Messages: []string{fmt.Sprintf("%s steps out of the shadows.", p.Name)},
+	})
+
+	p.Loc.Events <- &event
+}
+
+// refreshLocationFrames resends the location frame to everyone else at the players location.
+func (p *Entity) refreshLocationFrames() {
+	loc := p.Loc
+	if loc == nil {
+		return
+	}
+
+	loc.mu.Lock()
+	defer loc.mu.Unlock()
+
+	for client := range loc.Players {
+		if client.Player != nil && client.Player != p {
+			go client.Player.sendGameFrame(false)
+		}
+	}
+}
+
+// Spot reveals the hidden player to the searcher only.
+func (p *Entity) Spot(searcher *Entity) {
+	p.Mu.Lock()
+	if p.SpottedBy == nil {
+		p.SpottedBy = make(map[*Entity]bool)
+	}
+
+	p.SpottedBy[searcher] = true
+	p.Mu.Unlock()
+}
+
+// items which can turn up when searching around the city
+var HiddenLootTable = []string{
+	"goldchain",
+	"festivalticket",
+	"sunglasses",
+	"deliverypackage",
+	"currentthing",
+	"policebadge",
+	"weed",
+	"crack",
+	"switchblade",
+	"subsonic",
+}
+
+// SpawnHiddenLoot hides a random item at a random location in the city, up to settings.HiddenLootMaxPerCity.
+func (c *City) SpawnHiddenLoot() {
+	hidden := 0
+	for _, loc := range c.Grid {
+		loc.mu.Lock()
+		hidden += len(loc.HiddenItems)
+		loc.mu.Unlock()
+	}
+
+	if hidden >= settings.HiddenLootMaxPerCity {
+		return
+	}
+
+	item, ok := NewItem(HiddenLootTable[rand.Intn(len(HiddenLootTable))])
+	if !ok {
+		return
+	}
+
+	coords := c.RandomLocation()
+	loc := c.Grid[coords.toString()]
+
+	loc.mu.Lock()
+	loc.HiddenItems[item] = true
+	loc.mu.Unlock()
+}
+
+// FindHiddenItem takes a random hidden item from the location, if there are any.
+func (l *Location) FindHiddenItem() *Item {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for item := range l.HiddenItems {
+		delete(l.HiddenItems, item)
+		return item
+	}
+
+	return nil
+}
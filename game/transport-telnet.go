@@ -0,0 +1,298 @@
+package game
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/responses"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiPurple = "\x1b[35m"
+	ansiCyan   = "\x1b[36m"
+
+	telnetIAC = 255 // "interpret as command", starts telnet negotiation sequences
+	telnetSB  = 250 // starts a subnegotiation, eg. window size or terminal type
+	telnetSE  = 240 // ends a subnegotiation
+)
+
+// telnetTransport renders messages as ANSI text for MUD clients and plain terminals.
+// Messages without a text form, like stats or the map, are not sent.
+type telnetTransport struct {
+	conn         net.Conn
+	reader       *bufio.Reader
+	lastLocation string // the last location printed, so unchanged frames are not repeated
+	mu           sync.Mutex
+}
+
+func newTelnetTransport(conn net.Conn) *telnetTransport {
+	return &telnetTransport{
+		conn:   conn,
+		reader: bufio.NewReaderSize(conn, settings.MaxMessageSize),
+	}
+}
+
+func (t *telnetTransport) Read() (MessageKind, []byte, error) {
+	for {
+		t.conn.SetReadDeadline(time.Now().Add(settings.TelnetIdleMinutes * time.Minute))
+
+		line, err := t.reader.ReadSlice('\n')
+
+		// skip lines longer than the buffer, like the websocket read limit
+		if err == bufio.ErrBufferFull {
+			for err == bufio.ErrBufferFull {
+				_, err = t.reader.ReadSlice('\n')
+			}
+
+			if err != nil {
+				return MessageText, nil, err
+			}
+			continue
+		}
+
+		if err != nil {
+			return MessageText, nil, err
+		}
+
+		return MessageText, []byte(strings.TrimSpace(stripTelnetCommands(line))), nil
+	}
+}
+
+// stripTelnetCommands drops option negotiation the client sends along with its input.
+func stripTelnetCommands(line []byte) string {
+	out := make([]byte, 0, len(line))
+
+	for i := 0; i < len(line); i++ {
+		if line[i] != telnetIAC {
+			out = append(out, line[i])
+			continue
+		}
+
+		if i+1 >= len(line) {
+			break
+		}
+
+		switch cmd := line[i+1]; {
+		// an escaped 255 data byte
+		case cmd == telnetIAC:
+			out = append(out, telnetIAC)
+			i++
+		// IAC WILL/WONT/DO/DONT are followed by an option byte
+		case cmd >= 251 && cmd <= 254:
+			i += 2
+		// IAC SB <option> <data> IAC SE, the data is dropped up to the closing IAC SE, or the end of the line
+		case cmd == telnetSB:
+			i += 2
+			for i+1 < len(line) && !(line[i] == telnetIAC && line[i+1] == telnetSE) {
+				i++
+			}
+			i++
+		default:
+			i++
+		}
+	}
+
+	return string(out)
+}
+
+func (t *telnetTransport) Write(msgs []protoreflect.ProtoMessage, _ WriteOptions) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	out := strings.Builder{}
+	for _, msg := range msgs {
+		for _, line := range t.render(msg) {
+			out.WriteString(line)
+			out.WriteString(ansiReset + "\r\n")
+		}
+	}
+
+	if out.Len() == 0 {
+		return nil
+	}
+
+	t.conn.SetWriteDeadline(time.Now().Add(settings.WriteWait))
+	_, err := t.conn.Write([]byte(out.String()))
+	return err
+}
+
+func (t *telnetTransport) render(msg protoreflect.ProtoMessage) []string {
+	switch m := msg.(type) {
+	case *responses.Generic:
+		// ascii art and tables are already laid out
+		if m.Ascii {
+			lines := []string{}
+			for _, line := range m.Messages {
+				lines = append(lines, telnetSafe(line))
+			}
+			return lines
+		}
+
+		colour := ""
+		switch m.Status {
+		case responses.ResponseStatus_RESPONSE_STATUS_ERROR:
+			colour = ansiRed
+		case responses.ResponseStatus_RESPONSE_STATUS_WARN:
+			colour = ansiYellow
+		case responses.ResponseStatus_RESPONSE_STATUS_SUCCESS:
+			colour = ansiGreen
+		}
+
+		lines := []string{}
+		for _, line := range m.Messages {
+			lines = append(lines, colour+telnetSafe(line))
+		}
+		return lines
+
+	case *responses.Chat:
+		name := ""
+		if m.Player != nil {
+			name = playerName(m.Player)
+		}
+		text := telnetSafe(m.Msg)
+
+		switch m.Type {
+		case responses.ChatType_CHAT_TYPE_GLOBAL:
+			return []string{fmt.Sprintf("%s[Global] %s: %s", ansiCyan, name, text)}
+		case responses.ChatType_CHAT_TYPE_PRIVATE:
+			return []string{fmt.Sprintf("%s[PM] %s: %s", ansiPurple, name, text)}
+		case responses.ChatType_CHAT_TYPE_GANG:
+			return []string{fmt.Sprintf("%s[Gang] %s: %s", ansiGreen, name, text)}
+		}
+
+		return []string{fmt.Sprintf("%s: %s", name, text)}
+
+	case *responses.Location:
+		lines := renderLocation(m)
+		text := strings.Join(lines, "\n")
+
+		if !m.Clear && text == t.lastLocation {
+			return nil
+		}
+
+		t.lastLocation = text
+		return lines
+
+	case *responses.MerchantMessage:
+		return []string{ansiYellow + telnetSafe(m.Message)}
+
+	case *responses.NewsFlash:
+		return []string{ansiBold + ansiYellow + telnetSafe(m.Msg)}
+	}
+
+	return nil
+}
+
+func renderLocation(l *responses.Location) []string {
+	title := telnetSafe(l.CityName)
+	if l.Coordinates != nil {
+		title = fmt.Sprintf("%s (N%d, E%d)", title, l.Coordinates.North, l.Coordinates.East)
+	}
+
+	if l.GangTag != "" {
+		title = fmt.Sprintf("%s - turf of [%s]", title, telnetSafe(l.GangTag))
+	}
+
+	lines := []string{
+		"",
+		ansiBold + ansiBlue + title,
+		telnetSafe(l.Description),
+	}
+
+	for _, b := range l.Buildings {
+		lines = append(lines, fmt.Sprintf("%sThere is a %s here. %s", ansiCyan, telnetSafe(b.Name), telnetSafe(strings.Join(b.Commands, ", "))))
+	}
+
+	for _, p := range l.Players {
+		lines = append(lines, fmt.Sprintf("%s%s (%s) is here.", ansiGreen, playerName(p), telnetSafe(p.Rank)))
+	}
+
+	for _, npc := range l.Npcs {
+		lines = append(lines, fmt.Sprintf("%s%s is here.", ansiYellow, telnetSafe(npc.Name)))
+	}
+
+	for _, item := range l.Items {
+		lines = append(lines, fmt.Sprintf("%s lies on the ground.", telnetSafe(item.Name)))
+	}
+
+	return lines
+}
+
+// playerName is the player's name with their gang tag, made safe for the terminal.
+func playerName(p *responses.Player) string {
+	if p.GangTag == "" {
+		return telnetSafe(p.Name)
+	}
+
+	return fmt.Sprintf("[%s] %s", telnetSafe(p.GangTag), telnetSafe(p.Name))
+}
+
+// telnetSafe drops control characters, except tabs, so players cannot send escape sequences
+// to other players' terminals, eg. to clear the screen or fake server messages.
+func telnetSafe(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+
+		return r
+	}, text)
+}
+
+// telnet has no keep alive, idle clients are dropped by the read deadline instead
+func (t *telnetTransport) Ping() error {
+	return nil
+}
+
+func (t *telnetTransport) WriteClose() error {
+	t.conn.SetWriteDeadline(time.Now().Add(settings.WriteWait))
+	_, err := t.conn.Write([]byte("Connection closed.\r\n"))
+	return err
+}
+
+func (t *telnetTransport) Close() error {
+	return t.conn.Close()
+}
+
+// ListenTelnet accepts plain TCP connections, for classic MUD clients and scripts.
+func ListenTelnet(g *Game, addr string) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		logger.Logger.Error(fmt.Sprintf("Telnet gateway failed to start: %s", err))
+		return
+	}
+
+	logger.Logger.Info(fmt.Sprintf("Telnet gateway listening on: %s", addr))
+	logger.Logger.Warn("The telnet gateway is not encrypted, logins and passwords are sent as plain text.")
+
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return
+		}
+
+		if err != nil {
+			logger.Logger.Warn(fmt.Sprintf("Telnet accept error: %s", err))
+			continue
+		}
+
+		logger.Logger.Trace("New telnet connection.")
+		client := NewClient(g, newTelnetTransport(conn))
+		g.SendMOTD(client)
+	}
+}
@@ -0,0 +1,66 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mreliasen/swi-server/internal/responses"
+)
+
+func TestStripTelnetCommands(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "plain input", line: "/look\r\n", want: "/look\r\n"},
+		{name: "option negotiation", line: "\xff\xfb\x01\xff\xfd\x03/look\n", want: "/look\n"},
+		{name: "window size subnegotiation", line: "\xff\xfa\x1f\x00\x50\x00\x18\xff\xf0/look\n", want: "/look\n"},
+		{name: "terminal type subnegotiation", line: "\xff\xfa\x18\x00xterm\xff\xf0/say hi\n", want: "/say hi\n"},
+		{name: "subnegotiation without an end", line: "/say hi\xff\xfa\x18\x00xterm\n", want: "/say hi"},
+		{name: "escaped 255", line: "a\xff\xffb\n", want: "a\xffb\n"},
+		{name: "two byte command", line: "a\xff\xf1b\n", want: "ab\n"},
+		{name: "trailing IAC", line: "a\xff", want: "a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripTelnetCommands([]byte(tt.line)); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTelnetRenderStripsControlCharacters(t *testing.T) {
+	evil := "\x1b[2J\x1b]0;pwned\x07\rSYSTEM: \u009b31mhi\x7f\tthere"
+
+	tests := []struct {
+		name string
+		msg  *responses.Chat
+	}{
+		{name: "message", msg: &responses.Chat{Type: responses.ChatType_CHAT_TYPE_GLOBAL, Msg: evil, Player: &responses.Player{Name: "Tester"}}},
+		{name: "name", msg: &responses.Chat{Type: responses.ChatType_CHAT_TYPE_PRIVATE, Msg: "hi", Player: &responses.Player{Name: evil}}},
+		{name: "gang tag", msg: &responses.Chat{Type: responses.ChatType_CHAT_TYPE_GANG, Msg: "hi", Player: &responses.Player{Name: "Tester", GangTag: evil}}},
+	}
+
+	tr := &telnetTransport{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, line := range tr.render(tt.msg) {
+				// the only escape sequence left is the colour the line starts with
+				rest := strings.TrimPrefix(line, ansiCyan)
+				rest = strings.TrimPrefix(rest, ansiPurple)
+				rest = strings.TrimPrefix(rest, ansiGreen)
+
+				if strings.ContainsAny(rest, "\x1b\x07\r\x7f\u009b") {
+					t.Errorf("control characters left in %q", line)
+				}
+			}
+		})
+	}
+
+	if got := telnetSafe(evil); got != "[2J]0;pwnedSYSTEM: 31mhi\tthere" {
+		t.Errorf("telnetSafe returned %q", got)
+	}
+}
@@ -0,0 +1,201 @@
+package game
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/pterm/pterm"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// wsTransport sends Any wrapped protobuf messages over a websocket, for the web client.
+// Each binary frame holds one or more messages, each prefixed with its length as a varint once the
+// client has sent a handshake. Clients which never handshake get them separated by a newline.
+// In JSON mode messages are sent as protojson instead, one per text frame, for debugging.
+type wsTransport struct {
+	conn *websocket.Conn
+	json bool
+}
+
+func newWsTransport(conn *websocket.Conn, json bool) *wsTransport {
+	if json {
+		conn.SetReadLimit(settings.MaxJSONMessageSize)
+	} else {
+		conn.SetReadLimit(settings.MaxMessageSize)
+	}
+
+	conn.SetReadDeadline(time.Now().Add(settings.PongWait))
+	conn.SetPongHandler(func(string) error {
+		conn.SetReadDeadline(time.Now().Add(settings.PongWait))
+		return nil
+	})
+
+	return &wsTransport{conn: conn, json: json}
+}
+
+func (t *wsTransport) Read() (MessageKind, []byte, error) {
+	msgType, msg, err := t.conn.ReadMessage()
+	if err != nil {
+		if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+			logger.Logger.Warn(fmt.Sprintf("WS Error: %s", err))
+		}
+		return MessageText, nil, err
+	}
+
+	if msgType == websocket.BinaryMessage {
+		return MessageProto, msg, nil
+	}
+
+	// text commands still work in JSON mode
+	if t.json && bytes.HasPrefix(bytes.TrimSpace(msg), []byte("{")) {
+		return MessageJSON, msg, nil
+	}
+
+	return MessageText, msg, nil
+}
+
+func (t *wsTransport) Write(msgs []protoreflect.ProtoMessage, opts WriteOptions) error {
+	t.conn.SetWriteDeadline(time.Now().Add(settings.WriteWait))
+	t.conn.EnableWriteCompression(opts.Compress)
+
+	if t.json {
+		return t.writeJSON(msgs)
+	}
+
+	if !opts.Framed {
+		return t.writeJoined(msgs)
+	}
+
+	frame := []byte{}
+	for _, msg := range msgs {
+		wire, err := marshalAny(msg)
+		if err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		// start a new frame rather than growing one past MaxFrameSize
+		if len(frame) > 0 && len(frame)+protowire.SizeBytes(len(wire)) > settings.MaxFrameSize {
+			if err := t.conn.WriteMessage(websocket.BinaryMessage, frame); err != nil {
+				return err
+			}
+			frame = []byte{}
+		}
+
+		frame = protowire.AppendBytes(frame, wire)
+	}
+
+	if len(frame) == 0 {
+		return nil
+	}
+
+	return t.conn.WriteMessage(websocket.BinaryMessage, frame)
+}
+
+// writeJoined sends the batch in one binary frame with the messages separated by a newline,
+// the way clients from before the handshake expect them.
+func (t *wsTransport) writeJoined(msgs []protoreflect.ProtoMessage) error {
+	w, err := t.conn.NextWriter(websocket.BinaryMessage)
+	if err != nil {
+		return err
+	}
+
+	written := 0
+	for _, msg := range msgs {
+		wire, err := marshalAny(msg)
+		if err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		if written > 0 {
+			w.Write([]byte{'\n'})
+		}
+
+		w.Write(wire)
+		written++
+	}
+
+	return w.Close()
+}
+
+func marshalAny(msg protoreflect.ProtoMessage) ([]byte, error) {
+	m, err := anypb.New(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(m)
+}
+
+// writeJSON sends each message in its own text frame, with the type URL in "@type".
+func (t *wsTransport) writeJSON(msgs []protoreflect.ProtoMessage) error {
+	for _, msg := range msgs {
+		m, err := anypb.New(msg)
+		if err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		data, err := protojson.Marshal(m)
+		if err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		if err := t.conn.WriteMessage(websocket.TextMessage, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *wsTransport) Ping() error {
+	t.conn.SetWriteDeadline(time.Now().Add(settings.WriteWait))
+	return t.conn.WriteMessage(websocket.PingMessage, nil)
+}
+
+func (t *wsTransport) WriteClose() error {
+	t.conn.SetWriteDeadline(time.Now().Add(settings.WriteWait))
+	return t.conn.WriteMessage(websocket.CloseMessage, []byte{})
+}
+
+func (t *wsTransport) Close() error {
+	return t.conn.Close()
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+func HandleWsClient(g *Game, w http.ResponseWriter, r *http.Request) {
+	// permessage-deflate is only negotiated when the server has compression turned on
+	u := upgrader
+	u.EnableCompression = g.Compression
+
+	conn, err := u.Upgrade(w, r, nil)
+	if err != nil {
+		logger.Logger.Trace(pterm.Sprint(err))
+		return
+	}
+
+	logger.Logger.Trace("New connection.")
+	time.Sleep(500 * time.Millisecond)
+
+	client := NewClient(g, newWsTransport(conn, r.URL.Query().Get("format") == "json"))
+	g.SendMOTD(client)
+}
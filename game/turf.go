@@ -0,0 +1,333 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mreliasen/swi-server/game/settings"
+	"github.com/mreliasen/swi-server/internal/database/models"
+	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/responses"
+)
+
+// TurfGangTag returns the tag of the gang controlling the location, if any.
+func (l *Location) TurfGangTag() string {
+	l.turfMu.Lock()
+	defer l.turfMu.Unlock()
+
+	if l.Gang == nil {
+		return ""
+	}
+
+	return l.Gang.Tag
+}
+
+func (l *Location) TurfOwner() *Gang {
+	l.turfMu.Lock()
+	defer l.turfMu.Unlock()
+
+	return l.Gang
+}
+
+// AddClaimPoints progresses the gang's claim on the location. Once a gang has
+// enough points it takes over the turf. Points earned by the owning gang are
+// used to push back any challengers.
+func (l *Location) AddClaimPoints(gang *Gang, points int) {
+	if gang == nil || points <= 0 {
+		return
+	}
+
+	l.turfMu.Lock()
+
+	if l.Claims == nil {
+		l.Claims = make(map[*Gang]int)
+	}
+
+	if l.Gang == gang {
+		for challenger := range l.Claims {
+			l.Claims[challenger] -= points
+			if l.Claims[challenger] <= 0 {
+				delete(l.Claims, challenger)
+			}
+		}
+
+		l.turfMu.Unlock()
+		return
+	}
+
+	l.Claims[gang] += points
+	if l.Claims[gang] < settings.TurfClaimPoints {
+		l.turfMu.Unlock()
+		return
+	}
+
+	previous := l.Gang
+	l.Gang = gang
+	l.Claims = make(map[*Gang]int)
+	l.turfMu.Unlock()
+
+	l.saveTurf()
+
+	gang.Notify(&responses.Generic{
+		Status:   responses.ResponseStatus_RESPONSE_STATUS_SUCCESS,
+		Messages: []string{fmt.Sprintf("[%s] took control of %s in %s.", gang.Tag, l.Coords.toString(), l.City.Name)},
+	})
+
+	if previous != nil {
+		previous.Notify(&responses.Generic{
+			Status:   responses.ResponseStatus_RESPONSE_STATUS_WARN,
+			Messages: []string{fmt.Sprintf("[%s] took %s in %s from you.", gang.Tag, l.Coords.toString(), l.City.Name)},
+		})
+	}
+
+	l.City.refreshTurf(l)
+}
+
+// ReleaseTurf gives up the gang's hold on the location.
+func (l *Location) ReleaseTurf() {
+	l.turfMu.Lock()
+	l.Gang = nil
+	l.Claims = nil
+	l.turfMu.Unlock()
+
+	l.City.refreshTurf(l)
+}
+
+func (l *Location) saveTurf() {
+	owner := l.TurfOwner()
+	if owner == nil {
+		return
+	}
+
+	_, err := l.City.Game.DbConn.Exec(
+		"INSERT OR REPLACE INTO gang_turf (city, north, east, gang_id, claimed_at) VALUES (?, ?, ?, ?, ?)",
+		l.City.ShortName,
+		l.Coords.North,
+		l.Coords.East,
+		owner.ID,
+		time.Now().Unix(),
+	)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+}
+
+// TurfKill rewards the killer's gang for taking out an NPC or a rival at the location.
+func (l *Location) TurfKill(killer *Entity, victim *Entity) {
+	if !killer.IsPlayer || killer.Gang == nil {
+		return
+	}
+
+	if !victim.IsPlayer {
+		l.AddClaimPoints(killer.Gang, settings.TurfKillNpcPoints)
+		return
+	}
+
+	if victim.Gang != nil && victim.Gang != killer.Gang {
+		l.AddClaimPoints(killer.Gang, settings.TurfKillPlayerPoints)
+	}
+}
+
+// TakeTurfCut pays the owning gang its share of a drug sale and returns what is left for the seller.
+func (l *Location) TakeTurfCut(seller *Entity, price int64) int64 {
+	owner := l.TurfOwner()
+	if owner == nil {
+		return price
+	}
+
+	cut := int64(float64(price) * settings.TurfDrugSalesCut)
+	if cut <= 0 {
+		return price
+	}
+
+	owner.Mu.Lock()
+	owner.Bank += cut
+	RecordLedger(LedgerTurfCut, cut, WorldAccount, owner.BankAccount(), seller.Name)
+
+	err := owner.saveMoney(nil)
+	if err != nil {
+		owner.Bank -= cut
+		RecordLedger(LedgerReversal, cut, owner.BankAccount(), WorldAccount, string(LedgerTurfCut))
+		logger.Logger.Error(err.Error())
+	}
+	owner.Mu.Unlock()
+
+	if err != nil {
+		return price
+	}
+
+	logger.LogMoney(seller.Name, "turf-cut", cut, owner.Tag)
+	return price - cut
+}
+
+// refreshTurf updates the location frame for everyone there, and the map for everyone in the city.
+func (c *City) refreshTurf(l *Location) {
+	l.mu.Lock()
+	here := make([]*Client, 0, len(l.Players))
+	for client := range l.Players {
+		here = append(here, client)
+	}
+	l.mu.Unlock()
+
+	c.Mu.Lock()
+	inCity := make([]*Client, 0, len(c.Players))
+	for client := range c.Players {
+		inCity = append(inCity, client)
+	}
+	c.Mu.Unlock()
+
+	for _, client := range here {
+		go client.Player.sendGameFrame(false)
+	}
+
+	for _, client := range inCity {
+		if client.Player != nil {
+			go client.Player.PlayerSendMapUpdate()
+		}
+	}
+}
+
+// TurfTick awards presence points where rival gangs face off. The gang with the most members
+// at the location earns points for every member it outnumbers the runner up by. Standing on
+// turf nobody else contests earns nothing, it has to be taken with kills.
+func (c *City) TurfTick() {
+	present := map[*Location]map[*Gang]int{}
+
+	c.Mu.Lock()
+	for client := range c.Players {
+		p := client.Player
+		if p == nil || p.Gang == nil || p.Dead || p.Loc == nil || p.Loc.City != c {
+			continue
+		}
+
+		if present[p.Loc] == nil {
+			present[p.Loc] = make(map[*Gang]int)
+		}
+
+		present[p.Loc][p.Gang] += 1
+	}
+	c.Mu.Unlock()
+
+	for loc, gangs := range present {
+		if gang, lead := contestLeader(gangs); gang != nil {
+			loc.AddClaimPoints(gang, lead*settings.TurfPresencePoints)
+		}
+	}
+}
+
+// contestLeader returns the gang with the most members present and how many more it has than
+// the runner up. There is no leader when only one gang is present, or the top gangs are tied.
+func contestLeader(gangs map[*Gang]int) (*Gang, int) {
+	if len(gangs) < 2 {
+		return nil, 0
+	}
+
+	var leader *Gang
+	most, runnerUp := 0, 0
+
+	for gang, members := range gangs {
+		switch {
+		case members > most:
+			leader, most, runnerUp = gang, members, most
+		case members > runnerUp:
+			runnerUp = members
+		}
+	}
+
+	if most == runnerUp {
+		return nil, 0
+	}
+
+	return leader, most - runnerUp
+}
+
+func (c *City) turfFrames(viewer *Entity) []*responses.Turf {
+	frames := []*responses.Turf{}
+
+	for _, loc := range c.Grid {
+		owner := loc.TurfOwner()
+		if owner == nil {
+			continue
+		}
+
+		coords := loc.Coords
+		coords.City = c.ShortName
+
+		frames = append(frames, &responses.Turf{
+			Location: coords.toResponse(),
+			GangTag:  owner.Tag,
+			Own:      viewer.Gang == owner,
+		})
+	}
+
+	return frames
+}
+
+// Turf returns every location the gang controls.
+func (g *Gang) Turf() []*Location {
+	turf := []*Location{}
+
+	for _, city := range g.Game.World {
+		for _, loc := range city.Grid {
+			if loc.TurfOwner() == g {
+				turf = append(turf, loc)
+			}
+		}
+	}
+
+	return turf
+}
+
+func (g *Gang) releaseAllTurf() {
+	_, err := g.Game.DbConn.Exec("DELETE FROM gang_turf WHERE gang_id = ?", g.ID)
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+
+	for _, loc := range g.Turf() {
+		loc.ReleaseTurf()
+	}
+}
+
+// LoadTurf restores the saved gang territories onto the city grids.
+func (g *Game) LoadTurf() {
+	rows, err := g.DbConn.Query("SELECT city, north, east, gang_id FROM gang_turf")
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return
+	}
+
+	turf := []models.GangTurf{}
+	for rows.Next() {
+		t := models.GangTurf{}
+		if err := rows.Scan(&t.City, &t.North, &t.East, &t.GangId); err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		turf = append(turf, t)
+	}
+	rows.Close()
+
+	for _, t := range turf {
+		city, ok := g.World[t.City]
+		if !ok {
+			continue
+		}
+
+		coords := Coordinates{North: t.North, East: t.East}
+		loc, ok := city.Grid[coords.toString()]
+		if !ok {
+			continue
+		}
+
+		gang, err := g.GetGang(t.GangId)
+		if err != nil {
+			continue
+		}
+
+		loc.turfMu.Lock()
+		loc.Gang = gang
+		loc.turfMu.Unlock()
+	}
+}
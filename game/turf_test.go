@@ -0,0 +1,30 @@
+package game
+
+import "testing"
+
+func TestContestLeader(t *testing.T) {
+	a, b, c := &Gang{Tag: "A"}, &Gang{Tag: "B"}, &Gang{Tag: "C"}
+
+	tests := []struct {
+		name   string
+		gangs  map[*Gang]int
+		leader *Gang
+		lead   int
+	}{
+		{name: "nobody there", gangs: map[*Gang]int{}},
+		{name: "one gang alone", gangs: map[*Gang]int{a: 3}},
+		{name: "tied", gangs: map[*Gang]int{a: 2, b: 2}},
+		{name: "outnumbered", gangs: map[*Gang]int{a: 3, b: 1}, leader: a, lead: 2},
+		{name: "runner up decides the lead", gangs: map[*Gang]int{a: 1, b: 4, c: 2}, leader: b, lead: 2},
+		{name: "tied for the lead", gangs: map[*Gang]int{a: 3, b: 1, c: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			leader, lead := contestLeader(tt.gangs)
+			if leader != tt.leader || lead != tt.lead {
+				t.Errorf("got %v by %d, want %v by %d", leader, lead, tt.leader, tt.lead)
+			}
+		})
+	}
+}
@@ -0,0 +1,275 @@
+package game
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/mreliasen/swi-server/internal/database"
+	"github.com/mreliasen/swi-server/internal/database/models"
+	"github.com/mreliasen/swi-server/internal/logger"
+)
+
+const worldStateKey = "world"
+
+type WorldSnapshot struct {
+	NextRestock int64 `json:"next_restock"`
+}
+
+type CitySnapshot struct {
+	TravelCost  int64                `json:"travel_cost"`
+	DrugDemands map[string]float32   `json:"drug_demands"`
+	Items       []GroundItemSnapshot `json:"items"`
+	Npcs        []NpcSnapshot        `json:"npcs"`
+}
+
+type GroundItemSnapshot struct {
+	North     int               `json:"north"`
+	East      int               `json:"east"`
+	Hidden    bool              `json:"hidden"`
+	DroppedAt int64             `json:"dropped_at"`
+	Item      ItemSaveContainer `json:"item"`
+}
+
+type NpcSnapshot struct {
+	Type      NPCType `json:"type"`
+	Name      string  `json:"name"`
+	Gender    Gender  `json:"gender"`
+	Health    int     `json:"health"`
+	North     int     `json:"north"`
+	East      int     `json:"east"`
+	Inventory string  `json:"inventory"`
+}
+
+func cityStateKey(c *City) string {
+	return "city:" + c.ShortName
+}
+
+// Snapshot captures everything on the streets of the city which should survive a restart.
+func (c *City) Snapshot() CitySnapshot {
+	snapshot := CitySnapshot{
+		Items: []GroundItemSnapshot{},
+		Npcs:  []NpcSnapshot{},
+	}
+
+	c.Mu.Lock()
+	snapshot.TravelCost = c.TravelCost
+	snapshot.DrugDemands = make(map[string]float32, len(c.DrugDemands))
+	for drug, demand := range c.DrugDemands {
+		snapshot.DrugDemands[drug] = demand
+	}
+	c.Mu.Unlock()
+
+	for _, loc := range c.Grid {
+		loc.mu.Lock()
+		for item := range loc.Items {
+			snapshot.Items = append(snapshot.Items, groundItemSnapshot(loc, item, false))
+		}
+
+		for item := range loc.HiddenItems {
+			snapshot.Items = append(snapshot.Items, groundItemSnapshot(loc, item, true))
+		}
+		loc.mu.Unlock()
+	}
+
+	for _, npcs := range c.NPCs {
+		for npc := range npcs {
+			npc.Mu.Lock()
+			if npc.Loc == nil || npc.Dead {
+				npc.Mu.Unlock()
+				continue
+			}
+
+			state := NpcSnapshot{
+				Type:   npc.NpcType,
+				Name:   npc.Name,
+				Gender: npc.NpcGender,
+				Health: npc.Health,
+				North:  npc.Loc.Coords.North,
+				East:   npc.Loc.Coords.East,
+			}
+			npc.Mu.Unlock()
+
+			npc.Inventory.Mu.Lock()
+			inventory, err := npc.Inventory.serialize()
+			npc.Inventory.Mu.Unlock()
+			if err != nil {
+				logger.Logger.Error(err.Error())
+				continue
+			}
+
+			state.Inventory = inventory
+			snapshot.Npcs = append(snapshot.Npcs, state)
+		}
+	}
+
+	return snapshot
+}
+
+func groundItemSnapshot(loc *Location, item *Item, hidden bool) GroundItemSnapshot {
+	return GroundItemSnapshot{
+		North:     loc.Coords.North,
+		East:      loc.Coords.East,
+		Hidden:    hidden,
+		DroppedAt: item.DroppedAt,
+		Item: ItemSaveContainer{
+			ID:           item.ID,
+			TemplateName: item.TemplateName,
+			Condition:    item.Condition,
+			Amount:       uint(item.Amount),
+		},
+	}
+}
+
+// Restore puts the items and NPCs from the snapshot back on the streets, the city grid must be set up first.
+func (c *City) Restore(snapshot *CitySnapshot) {
+	c.Mu.Lock()
+	c.TravelCost = snapshot.TravelCost
+	if len(snapshot.DrugDemands) > 0 {
+		c.DrugDemands = snapshot.DrugDemands
+	}
+	c.Mu.Unlock()
+
+	for _, state := range snapshot.Items {
+		coords := Coordinates{North: state.North, East: state.East}
+		loc, ok := c.Grid[coords.toString()]
+		if !ok {
+			continue
+		}
+
+		item, ok := NewItem(state.Item.TemplateName)
+		if !ok {
+			continue
+		}
+
+		item.ID = state.Item.ID
+		item.Condition = state.Item.Condition
+		item.Amount = int32(state.Item.Amount)
+		item.Loc = loc
+		item.DroppedAt = state.DroppedAt
+		if item.DroppedAt == 0 {
+			item.DroppedAt = time.Now().Unix()
+		}
+
+		loc.mu.Lock()
+		if state.Hidden {
+			loc.HiddenItems[item] = true
+		} else {
+			loc.Items[item] = true
+		}
+		loc.mu.Unlock()
+	}
+
+	for _, state := range snapshot.Npcs {
+		if _, ok := NpcTemplates[state.Type]; !ok {
+			continue
+		}
+
+		coords := Coordinates{North: state.North, East: state.East}
+		loc, ok := c.Grid[coords.toString()]
+		if !ok {
+			continue
+		}
+
+		npc := NewNPC(state.Type)
+		npc.Name = state.Name
+		npc.NpcGender = state.Gender
+		npc.Health = state.Health
+
+		npc.Inventory = NewInventory(npc)
+		npc.Inventory.Mu.Lock()
+		npc.Inventory.deserialize(state.Inventory)
+		npc.Inventory.Mu.Unlock()
+
+		if c.NPCs[state.Type] == nil {
+			c.NPCs[state.Type] = make(map[*Entity]bool)
+		}
+
+		c.NPCs[state.Type][npc] = true
+		loc.NpcJoin <- npc
+	}
+}
+
+// SaveWorld writes a snapshot of every city, so a restart picks up where the streets left off.
+func (g *Game) SaveWorld() {
+	states := map[string]any{
+		worldStateKey: WorldSnapshot{
+			NextRestock: g.NextRestock.Load(),
+		},
+	}
+
+	for _, city := range g.World {
+		states[cityStateKey(city)] = city.Snapshot()
+	}
+
+	err := database.WithTx(g.DbConn, func(tx *sql.Tx) error {
+		for key, state := range states {
+			data, err := json.Marshal(state)
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(
+				"INSERT OR REPLACE INTO world_state (key, state, updated_at) VALUES (?, ?, ?)",
+				key,
+				string(data),
+				time.Now().Unix(),
+			)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Logger.Error(err.Error())
+	}
+}
+
+// LoadWorld reads back the last saved world snapshot, cities without a snapshot are left out.
+func (g *Game) LoadWorld() (*WorldSnapshot, map[string]*CitySnapshot) {
+	cities := map[string]*CitySnapshot{}
+
+	rows, err := g.DbConn.Query("SELECT key, state, updated_at FROM world_state")
+	if err != nil {
+		logger.Logger.Error(err.Error())
+		return nil, cities
+	}
+	defer rows.Close()
+
+	var world *WorldSnapshot
+
+	for rows.Next() {
+		state := models.WorldState{}
+		if err := rows.Scan(&state.Key, &state.State, &state.UpdatedAt); err != nil {
+			logger.Logger.Error(err.Error())
+			continue
+		}
+
+		if state.Key == worldStateKey {
+			world = &WorldSnapshot{}
+			if err := json.Unmarshal([]byte(state.State), world); err != nil {
+				logger.Logger.Error(err.Error())
+				world = nil
+			}
+			continue
+		}
+
+		for _, city := range g.World {
+			if cityStateKey(city) != state.Key {
+				continue
+			}
+
+			snapshot := CitySnapshot{}
+			if err := json.Unmarshal([]byte(state.State), &snapshot); err != nil {
+				logger.Logger.Error(err.Error())
+				break
+			}
+
+			cities[city.ShortName] = &snapshot
+		}
+	}
+
+	return world, cities
+}
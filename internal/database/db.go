@@ -2,10 +2,15 @@ package database
 
 import (
 	"database/sql"
+	"embed"
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 	_ "github.com/libsql/libsql-client-go/libsql"
 	"github.com/mreliasen/swi-server/internal/logger"
 	"github.com/pterm/pterm"
@@ -13,6 +18,9 @@ import (
 
 var dbConnection *sql.DB
 
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
 func Connect(dbUrl string) (*sql.DB, bool) {
 	if dbConnection != nil {
 		return dbConnection, true
@@ -31,24 +39,104 @@ func Connect(dbUrl string) (*sql.DB, bool) {
 	return dbConnection, true
 }
 
-func RunMigration(conn *sql.DB) {
+// Migrate brings the database schema up to date with the migrations embedded in
+// the binary. It refuses to touch a schema that is newer than this build.
+func Migrate(conn *sql.DB) error {
+	source, err := iofs.New(migrationFiles, "migrations")
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	latest, err := latestMigration(source)
+	if err != nil {
+		return fmt.Errorf("failed to load migrations: %w", err)
+	}
+
 	driver, err := sqlite.WithInstance(conn, &sqlite.Config{})
 	if err != nil {
-		logger.Logger.Fatal("Failed to run migrations")
-		fmt.Println(err)
-		return
+		return fmt.Errorf("failed to prepare migrations: %w", err)
+	}
+
+	// m.Close would close the shared connection as well, so it is left open
+	m, err := migrate.NewWithInstance("iofs", source, "swi", driver)
+	if err != nil {
+		return fmt.Errorf("failed to prepare migrations: %w", err)
+	}
+
+	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		version, err = baselineVersion(conn)
+		if err != nil {
+			return err
+		}
+
+		if version > 0 {
+			logger.Logger.Warn(fmt.Sprintf("Existing schema without migration history, marking it as version %d", version))
+			if err := m.Force(int(version)); err != nil {
+				return err
+			}
+		}
+	} else if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("schema version %d is dirty, fix the database and force the version before starting", version)
+	}
+
+	if version > latest {
+		return fmt.Errorf("schema version %d is newer than this server supports (%d), update the server", version, latest)
+	}
+
+	if version == latest {
+		logger.Logger.Info(fmt.Sprintf("DB schema is up to date (version %d).", version))
+		return nil
+	}
+
+	logger.Logger.Info(fmt.Sprintf("Migrating DB schema from version %d to %d..", version, latest))
+
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	logger.Logger.Info("DB migrations complete.")
+	return nil
+}
+
+func latestMigration(source source.Driver) (uint, error) {
+	version, err := source.First()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, os.ErrNotExist) {
+			return version, nil
+		}
+
+		if err != nil {
+			return 0, err
+		}
+
+		version = next
 	}
+}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://./migrations",
-		"swi",
-		driver,
-	)
+// baselineVersion marks a database created from the old migration.sql, which
+// matches migration 1, so migrate does not try to create its tables again.
+// Every later migration is only ever applied through migrate, so those databases
+// already have a version and never get here.
+func baselineVersion(conn *sql.DB) (uint, error) {
+	var count int
+	err := conn.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'users'").Scan(&count)
 	if err != nil {
-		logger.Logger.Fatal("Failed to find migrations")
-		fmt.Println(err)
-		return
+		return 0, err
+	}
+
+	if count == 0 {
+		return 0, nil
 	}
 
-	m.Up()
+	return 1, nil
 }
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `gangs`;
+DROP TABLE IF EXISTS `inventory`;
+DROP TABLE IF EXISTS `characters`;
+DROP TABLE IF EXISTS `users`;
@@ -45,6 +45,3 @@ CREATE TABLE IF NOT EXISTS `gangs` (
   `tag` text  UNIQUE,
   `leader_id` integer
 );
-
--- atlas schema apply --url "sqlite://./local.db" --to "file://./internal/database/migration.sql" --dev-url "sqlite://file?mode=memory"
--- atlas schema apply --env turso --to file://internal/database/migration.sql --dev-url "sqlite://file?mode=memory"
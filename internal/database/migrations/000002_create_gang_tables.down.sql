@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS `gang_wars_active_pair`;
+DROP TABLE IF EXISTS `gang_wars`;
+DROP TABLE IF EXISTS `gang_turf`;
+DROP TABLE IF EXISTS `gang_bank_log`;
+DROP TABLE IF EXISTS `gang_stash`;
+DROP TABLE IF EXISTS `gang_chat`;
+DROP TABLE IF EXISTS `gang_members`;
+DROP INDEX IF EXISTS `gangs_tag_nocase`;
+DROP INDEX IF EXISTS `gangs_name_nocase`;
+ALTER TABLE `gangs` DROP COLUMN `bank`;
+ALTER TABLE `gangs` DROP COLUMN `permissions`;
@@ -0,0 +1,72 @@
+ALTER TABLE `gangs` ADD COLUMN `permissions` text DEFAULT "" NOT NULL;
+ALTER TABLE `gangs` ADD COLUMN `bank` integer DEFAULT 0 NOT NULL;
+
+-- names and tags are unique regardless of case, so "Kings" and "kings" cannot both be created
+CREATE UNIQUE INDEX IF NOT EXISTS `gangs_name_nocase` ON `gangs` (`name` COLLATE NOCASE);
+CREATE UNIQUE INDEX IF NOT EXISTS `gangs_tag_nocase` ON `gangs` (`tag` COLLATE NOCASE);
+
+CREATE TABLE IF NOT EXISTS `gang_members` (
+  `character_id` integer PRIMARY KEY,
+  `gang_id` integer NOT NULL,
+  `role` integer DEFAULT 1 NOT NULL,
+  `joined_at` integer DEFAULT 0 NOT NULL,
+  `last_chat_id` integer DEFAULT 0 NOT NULL,
+  FOREIGN KEY(character_id) REFERENCES characters(id),
+  FOREIGN KEY(gang_id) REFERENCES gangs(id)
+);
+
+CREATE TABLE IF NOT EXISTS `gang_chat` (
+  `id` integer PRIMARY KEY,
+  `gang_id` integer NOT NULL,
+  `character_id` integer NOT NULL,
+  `name` text NOT NULL,
+  `message` text NOT NULL,
+  `created_at` integer DEFAULT 0 NOT NULL,
+  FOREIGN KEY(gang_id) REFERENCES gangs(id)
+);
+
+CREATE TABLE IF NOT EXISTS `gang_stash` (
+  `gang_id` integer PRIMARY KEY,
+  `inventory` text,
+  `updated_at` integer,
+  FOREIGN KEY(gang_id) REFERENCES gangs(id)
+);
+
+CREATE TABLE IF NOT EXISTS `gang_bank_log` (
+  `id` integer PRIMARY KEY,
+  `gang_id` integer NOT NULL,
+  `character_id` integer NOT NULL,
+  `action` text NOT NULL,
+  `amount` integer DEFAULT 0 NOT NULL,
+  `item` text DEFAULT "" NOT NULL,
+  `created_at` integer DEFAULT 0 NOT NULL,
+  FOREIGN KEY(gang_id) REFERENCES gangs(id)
+);
+
+CREATE TABLE IF NOT EXISTS `gang_turf` (
+  `city` text NOT NULL,
+  `north` integer NOT NULL,
+  `east` integer NOT NULL,
+  `gang_id` integer NOT NULL,
+  `claimed_at` integer DEFAULT 0 NOT NULL,
+  PRIMARY KEY(city, north, east),
+  FOREIGN KEY(gang_id) REFERENCES gangs(id)
+);
+
+CREATE TABLE IF NOT EXISTS `gang_wars` (
+  `id` integer PRIMARY KEY,
+  `attacker_id` integer NOT NULL,
+  `defender_id` integer NOT NULL,
+  `attacker_tag` text NOT NULL,
+  `defender_tag` text NOT NULL,
+  `attacker_score` integer DEFAULT 0 NOT NULL,
+  `defender_score` integer DEFAULT 0 NOT NULL,
+  `winner_id` integer DEFAULT 0 NOT NULL,
+  `result` text DEFAULT "" NOT NULL,
+  `started_at` integer DEFAULT 0 NOT NULL,
+  `ends_at` integer DEFAULT 0 NOT NULL,
+  `ended_at` integer DEFAULT 0 NOT NULL
+);
+
+-- only one active war between two gangs, whichever of them declared it
+CREATE UNIQUE INDEX IF NOT EXISTS `gang_wars_active_pair` ON `gang_wars` (min(`attacker_id`, `defender_id`), max(`attacker_id`, `defender_id`)) WHERE `ended_at` = 0;
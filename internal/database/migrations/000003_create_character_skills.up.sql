@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS `character_skills` (
+  `character_id` integer NOT NULL,
+  `skill` text NOT NULL,
+  `value` real DEFAULT 0.0 NOT NULL,
+  PRIMARY KEY(character_id, skill),
+  FOREIGN KEY(character_id) REFERENCES characters(id)
+);
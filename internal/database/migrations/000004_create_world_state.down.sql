@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `world_state`;
@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS `world_state` (
+  `key` text PRIMARY KEY,
+  `state` text NOT NULL,
+  `updated_at` integer DEFAULT 0 NOT NULL
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS `ledger_txn`;
+DROP INDEX IF EXISTS `ledger_account`;
+DROP TABLE IF EXISTS `ledger`;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS `ledger` (
+  `id` integer PRIMARY KEY,
+  `txn_id` text NOT NULL,
+  `account_type` text NOT NULL,
+  `account_id` integer DEFAULT 0 NOT NULL,
+  `amount` integer NOT NULL,
+  `balance` integer DEFAULT 0 NOT NULL,
+  `reason` text NOT NULL,
+  `ref` text DEFAULT "" NOT NULL,
+  `created_at` integer DEFAULT 0 NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS `ledger_account` ON `ledger` (`account_type`, `account_id`);
+CREATE INDEX IF NOT EXISTS `ledger_txn` ON `ledger` (`txn_id`);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS `server_keys`;
+DROP INDEX IF EXISTS `account_tokens_user`;
+DROP TABLE IF EXISTS `account_tokens`;
+ALTER TABLE `users` DROP COLUMN `verified`;
@@ -0,0 +1,23 @@
+ALTER TABLE `users` ADD COLUMN `verified` integer DEFAULT 0 NOT NULL;
+
+-- accounts created before email verification keep full access
+UPDATE `users` SET `verified` = 1;
+
+CREATE TABLE IF NOT EXISTS `account_tokens` (
+  `nonce` text PRIMARY KEY,
+  `user_id` integer NOT NULL,
+  `purpose` text NOT NULL,
+  `expires_at` integer DEFAULT 0 NOT NULL,
+  `used_at` integer DEFAULT 0 NOT NULL,
+  `created_at` integer DEFAULT 0 NOT NULL,
+  FOREIGN KEY(user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS `account_tokens_user` ON `account_tokens` (`user_id`, `purpose`);
+
+-- keys the server generates for itself, like the one account tokens are signed with
+CREATE TABLE IF NOT EXISTS `server_keys` (
+  `name` text PRIMARY KEY,
+  `key` text NOT NULL,
+  `created_at` integer DEFAULT 0 NOT NULL
+);
@@ -5,6 +5,7 @@ type User struct {
 	Email     string
 	Password  string
 	UserType  uint64
+	Verified  bool
 	CreatedAt uint64
 }
 
@@ -32,14 +33,74 @@ type Character struct {
 	CreatedAt     int64
 }
 
+type CharacterSkill struct {
+	CharacterId uint64
+	Skill       string
+	Value       float32
+}
+
 type Inventory struct {
 	UserId    uint64
 	Inventory string
 }
 
 type Gang struct {
-	Id       uint64
-	Name     string
-	Tag      string
-	LeaderID uint64
+	Id          uint64
+	Name        string
+	Tag         string
+	LeaderID    uint64
+	Permissions string
+	Bank        int64
+}
+
+type GangStash struct {
+	GangId    uint64
+	Inventory string
+	UpdatedAt int64
+}
+
+type GangMember struct {
+	CharacterId uint64
+	GangId      uint64
+	Role        uint8
+	JoinedAt    int64
+	LastChatId  uint64
+}
+
+type GangChat struct {
+	Id          uint64
+	GangId      uint64
+	CharacterId uint64
+	Name        string
+	Message     string
+	CreatedAt   int64
+}
+
+type GangTurf struct {
+	City      string
+	North     int
+	East      int
+	GangId    uint64
+	ClaimedAt int64
+}
+
+type GangWar struct {
+	Id            uint64
+	AttackerId    uint64
+	DefenderId    uint64
+	AttackerTag   string
+	DefenderTag   string
+	AttackerScore int
+	DefenderScore int
+	WinnerId      uint64
+	Result        string
+	StartedAt     int64
+	EndsAt        int64
+	EndedAt       int64
+}
+
+type WorldState struct {
+	Key       string
+	State     string
+	UpdatedAt int64
 }
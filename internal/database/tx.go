@@ -0,0 +1,46 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/mreliasen/swi-server/internal/logger"
+)
+
+const (
+	TxAttempts   = 3
+	TxRetryDelay = 100 * time.Millisecond
+)
+
+// WithTx runs fn inside a transaction and commits it, or rolls everything back if fn fails.
+// Failed transactions are retried a few times, so fn must only write to the database.
+func WithTx(conn *sql.DB, fn func(tx *sql.Tx) error) error {
+	var err error
+
+	for attempt := 1; attempt <= TxAttempts; attempt++ {
+		err = runTx(conn, fn)
+		if err == nil {
+			return nil
+		}
+
+		logger.Logger.Warn(fmt.Sprintf("Transaction failed (attempt %d/%d): %s", attempt, TxAttempts, err))
+		time.Sleep(TxRetryDelay * time.Duration(attempt))
+	}
+
+	return err
+}
+
+func runTx(conn *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
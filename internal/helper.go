@@ -18,7 +18,11 @@ var (
 	keyLength   uint32 = 32
 )
 
-var IsValidCharacterName = regexp.MustCompile(`^[a-zA-Z0-9_-]{3,15}$`).MatchString
+var (
+	IsValidCharacterName = regexp.MustCompile(`^[a-zA-Z0-9_-]{3,15}$`).MatchString
+	IsValidGangName      = regexp.MustCompile(`^[a-zA-Z0-9 _-]{3,24}$`).MatchString
+	IsValidGangTag       = regexp.MustCompile(`^[a-zA-Z0-9]{2,5}$`).MatchString
+)
 
 func PrintStruct(s any) {
 	fmt.Printf("%+v\n", s)
@@ -20,6 +20,8 @@ var (
 	logCombat       *log.Logger
 	logItems        *log.Logger
 	logChat         *log.Logger
+	logSkills       *log.Logger
+	logRateLimit    *log.Logger
 )
 
 func LogItems(name string, action string, item string, north int, east int, city string) {
@@ -42,6 +44,14 @@ func LogChat(msgType string, name string, message string, recipient string) {
 	logChat.Printf(",%s,%s,%s,%s", msgType, name, message, recipient)
 }
 
+func LogSkill(name string, skill string, value float32, success bool, target string, result string) {
+	logSkills.Printf(",%s,%s,%.4f,%t,%s,%s", name, skill, value, success, target, result)
+}
+
+func LogRateLimit(name string, category string, command string, action string) {
+	logRateLimit.Printf(",%s,%s,%s,%s", name, category, command, action)
+}
+
 func New(env *string) {
 	logLevel := pterm.LogLevelWarn
 
@@ -88,11 +98,25 @@ func New(env *string) {
 		os.Exit(1)
 	}
 
+	skills, err := os.OpenFile(logsDirPath+"/skills.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o666)
+	if err != nil {
+		fmt.Println("Error opening skills.log:", err)
+		os.Exit(1)
+	}
+
+	rateLimit, err := os.OpenFile(logsDirPath+"/ratelimit.log", os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o666)
+	if err != nil {
+		fmt.Println("Error opening ratelimit.log:", err)
+		os.Exit(1)
+	}
+
 	logTransactions = log.New(transactions, "Transactions ", log.LstdFlags)
 	logMoney = log.New(money, "Money", log.LstdFlags)
 	logCombat = log.New(combat, "Combat", log.LstdFlags)
 	logItems = log.New(items, "Items", log.LstdFlags)
 	logChat = log.New(chat, "Chat", log.LstdFlags)
+	logSkills = log.New(skills, "Skills", log.LstdFlags)
+	logRateLimit = log.New(rateLimit, "RateLimit", log.LstdFlags)
 	Logger = &logger
 }
 
@@ -0,0 +1,106 @@
+package mailer
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mreliasen/swi-server/internal/logger"
+)
+
+// Mailer sends account emails, like verification and password reset links.
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+// headers cannot contain line breaks, or extra headers could be injected through them
+func checkHeaders(values ...string) error {
+	for _, value := range values {
+		if strings.ContainsAny(value, "\r\n") {
+			return errors.New("invalid email header")
+		}
+	}
+
+	return nil
+}
+
+// SMTPMailer sends emails through an SMTP server, with PLAIN auth when a username is set.
+type SMTPMailer struct {
+	Addr     string // host:port
+	Username string
+	Password string
+	From     string
+}
+
+func NewSMTPMailer(addr string, username string, password string, from string) *SMTPMailer {
+	return &SMTPMailer{
+		Addr:     addr,
+		Username: username,
+		Password: password,
+		From:     from,
+	}
+}
+
+func (m *SMTPMailer) Send(to string, subject string, body string) error {
+	if err := checkHeaders(to, subject); err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	msg := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nMIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n",
+		m.From,
+		to,
+		subject,
+		strings.ReplaceAll(body, "\n", "\r\n"),
+	)
+
+	return smtp.SendMail(m.Addr, auth, m.From, []string{to}, []byte(msg))
+}
+
+// FileMailer appends emails to a file instead of sending them, for local development.
+type FileMailer struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func NewFileMailer(path string) *FileMailer {
+	return &FileMailer{Path: path}
+}
+
+func (m *FileMailer) Send(to string, subject string, body string) error {
+	if err := checkHeaders(to, subject); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.Path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o666)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n----\n\n", time.Now().Format(time.RFC1123Z), to, subject, body)
+	if err != nil {
+		return err
+	}
+
+	logger.Logger.Trace(fmt.Sprintf("Email to %s written to %s", to, m.Path))
+	return nil
+}
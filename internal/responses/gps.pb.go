@@ -33,6 +33,7 @@ type GPS struct {
 	Width    int32         `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`
 	Height   int32         `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
 	Name     string        `protobuf:"bytes,7,opt,name=name,proto3" json:"name,omitempty"`
+	Turf     []*Turf       `protobuf:"bytes,8,rep,name=turf,proto3" json:"turf,omitempty"`
 }
 
 func (x *GPS) Reset() {
@@ -116,6 +117,77 @@ func (x *GPS) GetName() string {
 	return ""
 }
 
+func (x *GPS) GetTurf() []*Turf {
+	if x != nil {
+		return x.Turf
+	}
+	return nil
+}
+
+// Turf is a location held by a gang, own is set for the player's gang.
+type Turf struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Location *Coordinate `protobuf:"bytes,1,opt,name=location,proto3" json:"location,omitempty"`
+	GangTag  string      `protobuf:"bytes,2,opt,name=gang_tag,json=gangTag,proto3" json:"gang_tag,omitempty"`
+	Own      bool        `protobuf:"varint,3,opt,name=own,proto3" json:"own,omitempty"`
+}
+
+func (x *Turf) Reset() {
+	*x = Turf{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_gps_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Turf) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Turf) ProtoMessage() {}
+
+func (x *Turf) ProtoReflect() protoreflect.Message {
+	mi := &file_gps_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Turf.ProtoReflect.Descriptor instead.
+func (*Turf) Descriptor() ([]byte, []int) {
+	return file_gps_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Turf) GetLocation() *Coordinate {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *Turf) GetGangTag() string {
+	if x != nil {
+		return x.GangTag
+	}
+	return ""
+}
+
+func (x *Turf) GetOwn() bool {
+	if x != nil {
+		return x.Own
+	}
+	return false
+}
+
 var File_gps_proto protoreflect.FileDescriptor
 
 var file_gps_proto_rawDesc = []byte{
@@ -123,7 +195,7 @@ var file_gps_proto_rawDesc = []byte{
 	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x1a, 0x19, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70,
 	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x61, 0x6e, 0x79, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x1a, 0x0c, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
-	0xf2, 0x01, 0x0a, 0x03, 0x47, 0x50, 0x53, 0x12, 0x31, 0x0a, 0x09, 0x61, 0x6e, 0x79, 0x5f, 0x66,
+	0x97, 0x02, 0x0a, 0x03, 0x47, 0x50, 0x53, 0x12, 0x31, 0x0a, 0x09, 0x61, 0x6e, 0x79, 0x5f, 0x66,
 	0x69, 0x65, 0x6c, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x67, 0x6f, 0x6f,
 	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x41, 0x6e, 0x79,
 	0x52, 0x08, 0x61, 0x6e, 0x79, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e,
@@ -138,9 +210,18 @@ var file_gps_proto_rawDesc = []byte{
 	0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67,
 	0x68, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
 	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x6e, 0x61, 0x6d, 0x65, 0x42, 0x20, 0x5a, 0x1e, 0x2e, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e,
-	0x61, 0x6c, 0x2f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x3b, 0x72, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x23, 0x0a, 0x04, 0x74, 0x75, 0x72, 0x66, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x2e, 0x54,
+	0x75, 0x72, 0x66, 0x52, 0x04, 0x74, 0x75, 0x72, 0x66, 0x22, 0x66, 0x0a, 0x04, 0x54, 0x75, 0x72,
+	0x66, 0x12, 0x31, 0x0a, 0x08, 0x6c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x2e,
+	0x43, 0x6f, 0x6f, 0x72, 0x64, 0x69, 0x6e, 0x61, 0x74, 0x65, 0x52, 0x08, 0x6c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x61, 0x6e, 0x67, 0x5f, 0x74, 0x61, 0x67,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x67, 0x61, 0x6e, 0x67, 0x54, 0x61, 0x67, 0x12,
+	0x10, 0x0a, 0x03, 0x6f, 0x77, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x6f, 0x77,
+	0x6e, 0x42, 0x20, 0x5a, 0x1e, 0x2e, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x3b, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -155,21 +236,24 @@ func file_gps_proto_rawDescGZIP() []byte {
 	return file_gps_proto_rawDescData
 }
 
-var file_gps_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_gps_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_gps_proto_goTypes = []interface{}{
 	(*GPS)(nil),        // 0: responses.GPS
-	(*anypb.Any)(nil),  // 1: google.protobuf.Any
-	(*Coordinate)(nil), // 2: responses.Coordinate
+	(*Turf)(nil),       // 1: responses.Turf
+	(*anypb.Any)(nil),  // 2: google.protobuf.Any
+	(*Coordinate)(nil), // 3: responses.Coordinate
 }
 var file_gps_proto_depIdxs = []int32{
-	1, // 0: responses.GPS.any_field:type_name -> google.protobuf.Any
-	2, // 1: responses.GPS.location:type_name -> responses.Coordinate
-	2, // 2: responses.GPS.pois:type_name -> responses.Coordinate
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	2, // 0: responses.GPS.any_field:type_name -> google.protobuf.Any
+	3, // 1: responses.GPS.location:type_name -> responses.Coordinate
+	3, // 2: responses.GPS.pois:type_name -> responses.Coordinate
+	1, // 3: responses.GPS.turf:type_name -> responses.Turf
+	3, // 4: responses.Turf.location:type_name -> responses.Coordinate
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_gps_proto_init() }
@@ -191,6 +275,18 @@ func file_gps_proto_init() {
 				return nil
 			}
 		}
+		file_gps_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Turf); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -198,7 +294,7 @@ func file_gps_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_gps_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -9,16 +9,29 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/mreliasen/swi-server/game"
+	"github.com/mreliasen/swi-server/game/settings"
 	"github.com/mreliasen/swi-server/internal/database"
 	"github.com/mreliasen/swi-server/internal/logger"
+	"github.com/mreliasen/swi-server/internal/mailer"
 )
 
 var (
 	env    = flag.String("env", "prod", "Environment")
 	domain = flag.String("domain", "swi-server.sirmre.com", "server domain (TLS)")
 	dburl  = flag.String("dburl", "ws://127.0.0.1:8080", "DB Url/path")
+
+	migrateOnly = flag.Bool("migrate-only", false, "Run the database migrations and exit")
+	telnet      = flag.String("telnet", "", "Address for the unencrypted telnet gateway, eg. 127.0.0.1:2323 (disabled when empty, credentials are sent in plain text)")
+	compression = flag.Bool("compression", false, "Negotiate permessage-deflate with clients which ask for compression")
+	batchWindow = flag.Duration("batch-window", settings.BatchWindowMs*time.Millisecond, "How long messages following a write are collected before the next write, 0 writes immediately")
+	smtpAddr    = flag.String("smtp", "", "SMTP server for account emails, eg. smtp.example.com:587 (written to logs/mail.log when empty)")
+	smtpUser    = flag.String("smtp-user", "", "SMTP username")
+	smtpPass    = flag.String("smtp-pass", "", "SMTP password")
+	mailFrom    = flag.String("mail-from", "noreply@swi-server.sirmre.com", "Sender address for account emails")
+	publicURL   = flag.String("public-url", "", "Base url for links in account emails (defaults to https://<domain>:8081)")
 )
 
 func CORS(h http.HandlerFunc) http.HandlerFunc {
@@ -49,13 +62,44 @@ func main() {
 
 	defer db.Close()
 
+	if err := database.Migrate(db); err != nil {
+		logger.Logger.Fatal(fmt.Sprintf("DB migration failed: %s", err))
+		os.Exit(1)
+		return
+	}
+
+	if *migrateOnly {
+		return
+	}
+
 	gameInstance := game.NewGame(db)
+	gameInstance.Compression = *compression
+	gameInstance.BatchWindow = *batchWindow
+
+	gameInstance.PublicURL = strings.TrimSuffix(*publicURL, "/")
+	if gameInstance.PublicURL == "" {
+		gameInstance.PublicURL = fmt.Sprintf("https://%s:8081", *domain)
+	}
+
+	if *smtpAddr != "" {
+		gameInstance.Mailer = mailer.NewSMTPMailer(*smtpAddr, *smtpUser, *smtpPass, *mailFrom)
+	} else if strings.ToLower(*env) != "dev" {
+		// without a mail server players can never verify their account or reset their password
+		logger.Logger.Warn("No --smtp server is set, emails are NOT being sent to players.")
+		logger.Logger.Warn("Verification and password reset emails are written to logs/mail.log instead, set --smtp to send them.")
+	} else {
+		logger.Logger.Info("Emails are written to logs/mail.log.")
+	}
 	go gameInstance.Run()
 
 	logger.Logger.Info("Game Ready!")
 
 	go StartWebServer(domain, gameInstance)
 
+	if *telnet != "" {
+		go game.ListenTelnet(gameInstance, *telnet)
+	}
+
 	if strings.ToLower(*env) == "prod" {
 		go gameInstance.RenderConsoleUI()
 	}
@@ -38,6 +38,8 @@ func StartWebServer(domain *string, gameInstance *game.Game) {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/register", CORS(gameInstance.HandleRegistration))
 	mux.HandleFunc("/check-name-taken", CORS(gameInstance.CheckNameTaken))
+	mux.HandleFunc("/verify", CORS(gameInstance.HandleVerify))
+	mux.HandleFunc("/reset-password", CORS(gameInstance.HandleResetPassword))
 	mux.HandleFunc("/", CORS(func(w http.ResponseWriter, r *http.Request) {
 		game.HandleWsClient(gameInstance, w, r)
 	}))